@@ -0,0 +1,4 @@
+data "vaultgrafanacloud_credentials" "creds" {
+  backend = "grafanacloud"
+  role    = "my-role"
+}
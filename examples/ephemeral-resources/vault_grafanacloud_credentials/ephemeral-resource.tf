@@ -0,0 +1,4 @@
+ephemeral "vaultgrafanacloud_credentials" "creds" {
+  backend = "grafanacloud"
+  role    = "my-role"
+}
@@ -6,16 +6,17 @@ provider "vault-grafanacloud" {
 
 resource "vaultgrafanacloud_secret_backend" "backend" {
   backend      = "vault-grafanacloud"
-  key          = var.grafana_cloud_api_key
+  key_wo       = var.grafana_cloud_api_key
+  key_version  = 1
   url          = var.grafana_cloud_api_url
   organisation = var.grafana_cloud_org
   user         = var.grafana_cloud_user
 }
 
 resource "vaultgrafanacloud_secret_role" "test" {
-  backend = "vault-grafanacloud"
+  backend = vaultgrafanacloud_secret_backend.backend.backend
   name    = "viewer-role"
   gc_role = "Viewer"
-  ttl     = "3600"
-  max_ttl = "3600"
+  ttl     = "1h"
+  max_ttl = "1h"
 }
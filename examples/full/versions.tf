@@ -0,0 +1,4 @@
+terraform {
+  # key_wo is a write-only attribute
+  required_version = ">= 1.11"
+}
@@ -0,0 +1,5 @@
+resource "vaultgrafanacloud_credential" "agent" {
+  backend              = "grafanacloud"
+  role                 = "my-role"
+  renew_before_seconds = 600
+}
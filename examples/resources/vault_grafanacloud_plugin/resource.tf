@@ -0,0 +1,14 @@
+resource "vaultgrafanacloud_plugin" "grafanacloud" {
+  sha256  = var.your_plugin_sha256
+  version = "v0.0.2"
+}
+
+resource "vaultgrafanacloud_secret_backend" "backend" {
+  backend        = "grafanacloud"
+  key_wo         = var.your_secret_api_key
+  key_version    = 1
+  url            = "https://grafana.com/api"
+  organisation   = "my-org"
+  user           = "my-user"
+  plugin_version = vaultgrafanacloud_plugin.grafanacloud.version
+}
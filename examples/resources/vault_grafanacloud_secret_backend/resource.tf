@@ -1,6 +1,7 @@
 resource "vaultgrafanacloud_secret_backend" "backend" {
   backend      = "grafanacloud"
-  key          = var.your_secret_api_key
+  key_wo       = var.your_secret_api_key
+  key_version  = 1
   url          = "https://grafana.com/api"
   organisation = "my-org"
   user         = "my-user"
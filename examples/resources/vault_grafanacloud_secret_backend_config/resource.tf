@@ -0,0 +1,8 @@
+resource "vaultgrafanacloud_secret_backend_config" "config" {
+  backend      = "grafanacloud"
+  key_wo       = var.your_secret_api_key
+  key_version  = 1
+  url          = "https://grafana.com/api"
+  organisation = "my-org"
+  user         = "my-user"
+}
@@ -1,7 +1,7 @@
 resource "vaultgrafanacloud_secret_role" "test" {
-  backend         = "grafanacloud"
-  name            = "my-role"
-  gc_role         = "Viewer"
-  ttl_seconds     = "3600"
-  max_ttl_seconds = "3600"
+  backend = "grafanacloud"
+  name    = "my-role"
+  gc_role = "Viewer"
+  ttl     = "1h"
+  max_ttl = "1h30m"
 }
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"log"
 
 	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/vaultgrafanacloud"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
@@ -13,8 +15,13 @@ func main() {
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
+	providerServer, err := vaultgrafanacloud.ProviderServer(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: vaultgrafanacloud.Provider,
-		Debug:        debugMode,
+		GRPCProviderFunc: providerServer,
+		Debug:            debugMode,
 	})
 }
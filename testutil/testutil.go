@@ -13,6 +13,11 @@ import (
 
 const (
 	EnvVarSkipVaultNext = "SKIP_VAULT_NEXT_TESTS"
+
+	EnvVarGrafanaCloudKey          = "GRAFANA_CLOUD_KEY"
+	EnvVarGrafanaCloudURL          = "GRAFANA_CLOUD_URL"
+	EnvVarGrafanaCloudOrganisation = "GRAFANA_CLOUD_ORGANISATION"
+	EnvVarGrafanaCloudUser         = "GRAFANA_CLOUD_USER"
 )
 
 func TestAccPreCheck(t *testing.T) {
@@ -24,6 +29,14 @@ func TestEntPreCheck(t *testing.T) {
 	TestAccPreCheck(t)
 }
 
+// TestCredsPreCheck skips tests that issue Grafana Cloud credentials unless
+// a Grafana Cloud API to issue them against is configured. It returns the
+// key, url, organisation and user of the API.
+func TestCredsPreCheck(t *testing.T) []string {
+	TestAccPreCheck(t)
+	return SkipTestEnvUnset(t, EnvVarGrafanaCloudKey, EnvVarGrafanaCloudURL, EnvVarGrafanaCloudOrganisation, EnvVarGrafanaCloudUser)
+}
+
 func SkipTestAcc(t *testing.T) {
 	SkipTestEnvUnset(t, resource.TestEnvVar)
 }
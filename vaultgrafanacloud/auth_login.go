@@ -0,0 +1,226 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+const (
+	authLoginApproleBlock    = "auth_login_approle"
+	authLoginKubernetesBlock = "auth_login_kubernetes"
+	authLoginJWTBlock        = "auth_login_jwt"
+
+	defaultApproleMount    = "approle"
+	defaultKubernetesMount = "kubernetes"
+	defaultJWTMount        = "jwt"
+
+	// defaultKubernetesJWTFile is where Kubernetes projects the pod's service
+	// account token.
+	defaultKubernetesJWTFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+var authLoginBlocks = []string{
+	authLoginApproleBlock,
+	authLoginKubernetesBlock,
+	authLoginJWTBlock,
+}
+
+// authLogin describes a login request against a Vault auth method.
+type authLogin struct {
+	method string
+	mount  string
+	data   map[string]interface{}
+}
+
+func (l *authLogin) path() string {
+	return fmt.Sprintf("auth/%s/login", strings.Trim(l.mount, "/"))
+}
+
+func authLoginApproleSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: authLoginConflicts(authLoginApproleBlock),
+		Description:   "Login to Vault using the AppRole auth method. Takes precedence over token.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"role_id": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The AppRole role ID.",
+				},
+				"secret_id": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					Description: "The AppRole secret ID. May be omitted if the role does not require one.",
+				},
+				"mount": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     defaultApproleMount,
+					Description: "The mount path of the AppRole auth method.",
+				},
+			},
+		},
+	}
+}
+
+func authLoginApprole(block map[string]interface{}) (*authLogin, error) {
+	data := map[string]interface{}{
+		"role_id": block["role_id"].(string),
+	}
+	if v := block["secret_id"].(string); v != "" {
+		data["secret_id"] = v
+	}
+	return &authLogin{
+		method: "approle",
+		mount:  block["mount"].(string),
+		data:   data,
+	}, nil
+}
+
+func authLoginKubernetesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: authLoginConflicts(authLoginKubernetesBlock),
+		Description:   "Login to Vault using the Kubernetes auth method. Takes precedence over token.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"role": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The Kubernetes auth role to login as.",
+				},
+				"jwt_file": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     defaultKubernetesJWTFile,
+					Description: "Path to the file containing the service account JWT.",
+				},
+				"mount": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     defaultKubernetesMount,
+					Description: "The mount path of the Kubernetes auth method.",
+				},
+			},
+		},
+	}
+}
+
+func authLoginKubernetes(block map[string]interface{}) (*authLogin, error) {
+	jwtFile := block["jwt_file"].(string)
+	jwt, err := os.ReadFile(jwtFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading service account JWT from %q: %s", jwtFile, err)
+	}
+	return &authLogin{
+		method: "kubernetes",
+		mount:  block["mount"].(string),
+		data: map[string]interface{}{
+			"role": block["role"].(string),
+			"jwt":  strings.TrimSpace(string(jwt)),
+		},
+	}, nil
+}
+
+func authLoginJWTSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: authLoginConflicts(authLoginJWTBlock),
+		Description:   "Login to Vault using the JWT/OIDC auth method. Takes precedence over token.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"role": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "The JWT auth role to login as.",
+				},
+				"jwt": {
+					Type:        schema.TypeString,
+					Required:    true,
+					Sensitive:   true,
+					Description: "The signed JWT to login with.",
+				},
+				"mount": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Default:     defaultJWTMount,
+					Description: "The mount path of the JWT auth method.",
+				},
+			},
+		},
+	}
+}
+
+func authLoginJWT(block map[string]interface{}) (*authLogin, error) {
+	return &authLogin{
+		method: "jwt",
+		mount:  block["mount"].(string),
+		data: map[string]interface{}{
+			"role": block["role"].(string),
+			"jwt":  block["jwt"].(string),
+		},
+	}, nil
+}
+
+// authLoginConflicts returns every auth_login_* block except the given one.
+func authLoginConflicts(block string) []string {
+	var conflicts []string
+	for _, b := range authLoginBlocks {
+		if b != block {
+			conflicts = append(conflicts, b)
+		}
+	}
+	return conflicts
+}
+
+// providerAuthLogin returns the login described by the configured
+// auth_login_* block, or nil if there is none.
+func providerAuthLogin(d *schema.ResourceData) (*authLogin, error) {
+	builders := map[string]func(map[string]interface{}) (*authLogin, error){
+		authLoginApproleBlock:    authLoginApprole,
+		authLoginKubernetesBlock: authLoginKubernetes,
+		authLoginJWTBlock:        authLoginJWT,
+	}
+	for _, block := range authLoginBlocks {
+		if v, ok := d.GetOk(block); ok {
+			return builders[block](v.([]interface{})[0].(map[string]interface{}))
+		}
+	}
+	return nil, nil
+}
+
+// login performs the login and returns the resulting client token.
+func (l *authLogin) login(client *api.Client) (string, error) {
+	// login requests must not carry a token, which api.NewClient may have
+	// picked up from the environment
+	client, err := client.Clone()
+	if err != nil {
+		return "", fmt.Errorf("error cloning Vault client: %s", err)
+	}
+	client.ClearToken()
+
+	path := l.path()
+	log.Printf("[DEBUG] Logging in to Vault using the %s auth method at %q", l.method, path)
+	resp, err := client.Logical().Write(path, l.data)
+	if err != nil {
+		return "", fmt.Errorf("error logging in to Vault using %s at %q: %s", l.method, path, err)
+	}
+	if resp == nil || resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("no client token returned by %s login at %q", l.method, path)
+	}
+	log.Printf("[DEBUG] Logged in to Vault using the %s auth method at %q", l.method, path)
+	return resp.Auth.ClientToken, nil
+}
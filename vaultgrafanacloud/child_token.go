@@ -0,0 +1,39 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// createChildToken issues a short-lived child of the client's token, named
+// after tokenName so the provider can be identified in Vault audit logs. The
+// provider is killed rather than shut down at the end of a Terraform run, so
+// the child token is never revoked, and expires at the end of its TTL
+// instead.
+func createChildToken(client *api.Client, tokenName string, maxTTL int) (string, error) {
+	ttl := fmt.Sprintf("%ds", maxTTL)
+	log.Printf("[DEBUG] Creating child token %q with a TTL of %s", tokenName, ttl)
+	resp, err := client.Auth().Token().Create(&api.TokenCreateRequest{
+		DisplayName:    tokenName,
+		TTL:            ttl,
+		ExplicitMaxTTL: ttl,
+	})
+	if err != nil {
+		return "", vaultError(err, "creating child token at", "auth/token/create", "update")
+	}
+	if resp == nil || resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("no client token returned when creating child token")
+	}
+	log.Printf("[DEBUG] Created child token %q with accessor %q", tokenName, resp.Auth.Accessor)
+	return resp.Auth.ClientToken, nil
+}
+
+// isChildToken returns whether the provider makes its requests with a child
+// token issued by providerConfigure. Vault revokes the child token, along with
+// every lease it created, once its TTL of max_lease_ttl_seconds runs out.
+func isChildToken(meta interface{}) bool {
+	m := meta.(*providerMeta)
+	return m.client != m.parentClient
+}
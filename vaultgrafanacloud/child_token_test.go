@@ -0,0 +1,27 @@
+package vaultgrafanacloud
+
+import (
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+)
+
+func TestIsChildToken(t *testing.T) {
+	parent, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent.SetToken("parent")
+	if isChildToken(testProviderMeta(parent)) {
+		t.Error("expected a provider using its configured token not to use a child token")
+	}
+
+	child, err := parent.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	child.SetToken("child")
+	if !isChildToken(&providerMeta{client: child, parentClient: parent}) {
+		t.Error("expected a provider holding a child token to use it")
+	}
+}
@@ -0,0 +1,123 @@
+package vaultgrafanacloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+func GrafanaCloudCredentialsDataSource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: grafanaCloudCredentialsDataSourceRead,
+
+		Schema: map[string]*schema.Schema{
+			"backend": {
+				Type:        schema.TypeString,
+				Default:     "grafana-cloud",
+				Optional:    true,
+				Description: "The mount path of the Grafana Cloud backend.",
+			},
+			"namespace": namespaceDataSourceSchema(),
+			"role": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the role to issue credentials for",
+			},
+			"key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The issued Grafana Cloud API key",
+			},
+			"user": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The User configured on the backend, needed to interact with prometheus",
+			},
+			"lease_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The lease ID of the issued credentials",
+			},
+			"lease_duration": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The lease duration of the issued credentials in seconds",
+			},
+			"lease_renewable": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the lease of the issued credentials is renewable",
+			},
+		},
+	}
+}
+
+// grafanaCloudCredentialsDataSourceRead issues credentials, warning when
+// they are issued with the provider's child token, as the token expiring
+// revokes their lease too.
+func grafanaCloudCredentialsDataSourceRead(_ context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	backend := normaliseBackend(d.Get("backend"))
+	credsPath := fmt.Sprintf("%s/creds/%s", backend, d.Get("role").(string))
+
+	log.Printf("[DEBUG] Reading %q", credsPath)
+	resp, err := client.Logical().Read(credsPath)
+	if err != nil {
+		return diag.FromErr(vaultError(err, "reading", credsPath, "read"))
+	}
+	log.Printf("[DEBUG] Read %q", credsPath)
+	if resp == nil {
+		return diag.Errorf("no credentials returned by %q", credsPath)
+	}
+
+	// credentials without a lease are identified by where they came from
+	if resp.LeaseID != "" {
+		d.SetId(resp.LeaseID)
+	} else {
+		d.SetId(credsPath)
+	}
+	if err := d.Set("backend", backend); err != nil {
+		return diag.Errorf("error setting backend: %s", err)
+	}
+	if err := setGrafanaCloudCredentials(d, resp); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if resp.LeaseID == "" || !isChildToken(meta) {
+		return nil
+	}
+	return diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  "Credentials are revoked along with the provider's child token",
+			Detail: fmt.Sprintf("The credentials issued by %q belong to the provider's child token, which expires after max_lease_ttl_seconds and revokes their lease %q with it. "+
+				"Set skip_child_token in the provider configuration to issue credentials that outlive the child token.", credsPath, resp.LeaseID),
+		},
+	}
+}
+
+// setGrafanaCloudCredentials sets the credentials and lease attributes from a
+// response of <backend>/creds/<role>.
+func setGrafanaCloudCredentials(d *schema.ResourceData, resp *api.Secret) error {
+	fields := map[string]interface{}{
+		"key":             resp.Data["key"],
+		"user":            resp.Data["user"],
+		"lease_id":        resp.LeaseID,
+		"lease_duration":  resp.LeaseDuration,
+		"lease_renewable": resp.Renewable,
+	}
+	for k, v := range fields {
+		if err := d.Set(k, v); err != nil {
+			return fmt.Errorf("error setting state key '%s': %s", k, err)
+		}
+	}
+	return nil
+}
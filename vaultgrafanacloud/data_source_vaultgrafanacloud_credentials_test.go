@@ -0,0 +1,116 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestGrafanaCloudCredentialsDataSource(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	testutil.SkipTestAcc(t)
+	grafanaCloud := testutil.TestCredsPreCheck(t)
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudCredentialsDataSource_config(backend, grafanaCloud),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_credentials.test", "backend", backend),
+					resource.TestCheckResourceAttrSet("data.vaultgrafanacloud_credentials.test", "key"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_credentials.test", "user", grafanaCloud[3]),
+					resource.TestCheckResourceAttrSet("data.vaultgrafanacloud_credentials.test", "lease_id"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_credentials.test", "lease_duration", "300"),
+				),
+			},
+		},
+	})
+}
+
+func TestGrafanaCloudCredentialsDataSource_skipChildToken(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	testutil.SkipTestAcc(t)
+	grafanaCloud := testutil.TestCredsPreCheck(t)
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudCredentialsSkipChildTokenConfig + testGrafanaCloudCredentialsDataSource_config(backend, grafanaCloud),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.vaultgrafanacloud_credentials.test", "lease_id"),
+					testAccGrafanaCloudCredentialsOutliveRun("data.vaultgrafanacloud_credentials.test"),
+				),
+			},
+		},
+	})
+}
+
+// testGrafanaCloudCredentialsSkipChildTokenConfig configures the provider to
+// issue credentials with its own token, so they outlive the run.
+const testGrafanaCloudCredentialsSkipChildTokenConfig = `
+provider "vaultgrafanacloud" {
+	skip_child_token = true
+}
+`
+
+// testAccGrafanaCloudCredentialsOutliveRun checks the lease of the
+// credentials survives the provider's child token, if any, which Vault revokes
+// once its TTL runs out. The check revokes this provider's child token early
+// rather than waiting for it to expire.
+func testAccGrafanaCloudCredentialsOutliveRun(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%q not found in state", name)
+		}
+		leaseID := rs.Primary.Attributes["lease_id"]
+
+		meta := testProvider.Meta().(*providerMeta)
+		if isChildToken(meta) {
+			if err := meta.client.Auth().Token().RevokeSelf(""); err != nil {
+				return fmt.Errorf("error revoking child token: %s", err)
+			}
+		}
+		if _, err := meta.parentClient.Sys().Lookup(leaseID); err != nil {
+			return fmt.Errorf("expected lease %q to outlive the run: %s", leaseID, err)
+		}
+		return nil
+	}
+}
+
+// testGrafanaCloudCredentialsConfig returns a backend configured against the
+// Grafana Cloud API from testutil.TestCredsPreCheck, with a role to issue
+// credentials for.
+func testGrafanaCloudCredentialsConfig(backend string, grafanaCloud []string) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key = "%s"
+	url = "%s"
+	organisation = "%s"
+	user = "%s"
+}
+
+resource "vaultgrafanacloud_secret_role" "test" {
+	backend = vaultgrafanacloud_secret_backend.test.backend
+	name = "test"
+	gc_role = "Viewer"
+}
+`, backend, grafanaCloud[0], grafanaCloud[1], grafanaCloud[2], grafanaCloud[3])
+}
+
+func testGrafanaCloudCredentialsDataSource_config(backend string, grafanaCloud []string) string {
+	return testGrafanaCloudCredentialsConfig(backend, grafanaCloud) + `
+data "vaultgrafanacloud_credentials" "test" {
+	backend = "/${vaultgrafanacloud_secret_role.test.backend}/"
+	role = vaultgrafanacloud_secret_role.test.name
+}`
+}
@@ -0,0 +1,114 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func GrafanaCloudSecretBackendDataSource() *schema.Resource {
+	return &schema.Resource{
+		Read: grafanaCloudSecretBackendDataSourceRead,
+
+		Schema: map[string]*schema.Schema{
+			"backend": {
+				Type:        schema.TypeString,
+				Default:     "grafana-cloud",
+				Optional:    true,
+				Description: "The mount path of the Grafana Cloud backend.",
+			},
+			"namespace": namespaceDataSourceSchema(),
+			"url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL for the Grafana Cloud API",
+			},
+			"organisation": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Organisation slug for the Grafana Cloud API",
+			},
+			"user": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The User that is needed to interact with prometheus",
+			},
+			"accessor": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The accessor of the mount",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The description of the mount",
+			},
+			"default_lease_ttl_seconds": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The default lease TTL of the mount in seconds",
+			},
+			"max_lease_ttl_seconds": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The maximum lease TTL of the mount in seconds",
+			},
+			"plugin_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the plugin the mount runs",
+			},
+		},
+	}
+}
+
+func grafanaCloudSecretBackendDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+	backend := normaliseBackend(d.Get("backend"))
+
+	// the config is only read once the mount is known to be a Grafana Cloud
+	// backend, whose config it is
+	log.Printf("[DEBUG] Reading mount %q", backend)
+	mount, err := getGrafanaCloudMount(client, backend)
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Read mount %q", backend)
+	if mount == nil {
+		return fmt.Errorf("mount %q not found", backend)
+	}
+
+	configPath := fmt.Sprintf("%s/config", backend)
+	log.Printf("[DEBUG] Reading %q", configPath)
+	resp, err := client.Logical().Read(configPath)
+	if err != nil {
+		return vaultError(err, "reading", configPath, "read")
+	}
+	log.Printf("[DEBUG] Read %q", configPath)
+	if resp == nil {
+		return fmt.Errorf("backend %q not found", backend)
+	}
+
+	d.SetId(backend)
+	fields := map[string]interface{}{
+		"backend":                   backend,
+		"url":                       resp.Data["url"],
+		"organisation":              resp.Data["organisation"],
+		"user":                      resp.Data["user"],
+		"accessor":                  mount.Accessor,
+		"description":               mount.Description,
+		"default_lease_ttl_seconds": mount.Config.DefaultLeaseTTL,
+		"max_lease_ttl_seconds":     mount.Config.MaxLeaseTTL,
+		"plugin_version":            mount.PluginVersion,
+	}
+	for k, v := range fields {
+		if err := d.Set(k, v); err != nil {
+			return fmt.Errorf("error setting state key '%s': %s", k, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package vaultgrafanacloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+func TestGrafanaCloudSecretBackendDataSource(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretBackendDataSource_config(backend),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_backend.test", "backend", backend),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_backend.test", "url", "http://localhost"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_backend.test", "organisation", "test_org"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_backend.test", "user", "user"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_backend.test", "description", "test backend"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_backend.test", "default_lease_ttl_seconds", "60"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_backend.test", "max_lease_ttl_seconds", "120"),
+					resource.TestCheckResourceAttrSet("data.vaultgrafanacloud_secret_backend.test", "accessor"),
+					resource.TestCheckNoResourceAttr("data.vaultgrafanacloud_secret_backend.test", "key"),
+				),
+			},
+		},
+	})
+}
+
+// TestGrafanaCloudSecretBackendDataSource_otherMount checks a different
+// secrets engine mounted at the backend's path is reported rather than its
+// config read.
+func TestGrafanaCloudSecretBackendDataSource_otherMount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/sys/mounts":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"grafana-cloud/": map[string]interface{}{"type": "kv", "accessor": "kv_1234"},
+				},
+			})
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	config.MaxRetries = 0
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := GrafanaCloudSecretBackendDataSource()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"backend": "grafana-cloud",
+	})
+	err = r.Read(d, testProviderMeta(client))
+	if err == nil || !strings.Contains(err.Error(), `is a "kv" secrets engine`) {
+		t.Errorf("expected an error naming the other secrets engine, got %v", err)
+	}
+}
+
+func testGrafanaCloudSecretBackendDataSource_config(backend string) string {
+	return testGrafanaCloudSecretBackendsConfig(backend) + `
+data "vaultgrafanacloud_secret_backend" "test" {
+	backend = vaultgrafanacloud_secret_backend.test.backend
+}`
+}
@@ -0,0 +1,198 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+func GrafanaCloudSecretBackendsDataSource() *schema.Resource {
+	return &schema.Resource{
+		Read: grafanaCloudSecretBackendsDataSourceRead,
+
+		Schema: map[string]*schema.Schema{
+			"namespace": namespaceDataSourceSchema(),
+			"plugin_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     grafanaCloudPluginType,
+				Description: "The name the Grafana Cloud plugin is registered under in the plugin catalog",
+			},
+			"recursive": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to also find the backends in every child namespace, recursively. Otherwise only the backends in the namespace itself are found",
+			},
+			"paths": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The mount paths of the Grafana Cloud backends, prefixed with the path of their child namespace, if any",
+			},
+			"backends": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The Grafana Cloud backends",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"backend": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The mount path of the backend in its namespace",
+						},
+						"namespace": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The namespace of the backend",
+						},
+						"accessor": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The accessor of the mount",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The description of the mount",
+						},
+						"default_lease_ttl_seconds": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The default lease TTL of the mount in seconds",
+						},
+						"max_lease_ttl_seconds": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The maximum lease TTL of the mount in seconds",
+						},
+						"plugin_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The version of the plugin the mount runs",
+						},
+						"local": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the mount is local to the cluster",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func grafanaCloudSecretBackendsDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+	pluginName := d.Get("plugin_name").(string)
+
+	namespaces := []string{""}
+	if d.Get("recursive").(bool) {
+		children, err := listChildNamespaces(client, "")
+		if err != nil {
+			return err
+		}
+		namespaces = append(namespaces, children...)
+	}
+
+	paths := []string{}
+	backends := []map[string]interface{}{}
+	for _, namespace := range namespaces {
+		nsClient := client
+		if namespace != "" {
+			nsClient = client.WithNamespace(strings.Trim(client.Namespace()+"/"+namespace, "/"))
+		}
+
+		log.Printf("[DEBUG] Reading mounts of namespace %q", nsClient.Namespace())
+		mounts, err := nsClient.Sys().ListMounts()
+		if err != nil {
+			return vaultError(err, "reading", strings.Trim(namespace+"/sys/mounts", "/"), "read")
+		}
+		log.Printf("[DEBUG] Read mounts of namespace %q", nsClient.Namespace())
+
+		nsPaths := []string{}
+		for path, mount := range mounts {
+			if mount.Type == pluginName {
+				nsPaths = append(nsPaths, normaliseBackend(path))
+			}
+		}
+		sort.Strings(nsPaths)
+
+		for _, path := range nsPaths {
+			mount := mounts[path+"/"]
+			paths = append(paths, strings.Trim(namespace+"/"+path, "/"))
+			backends = append(backends, map[string]interface{}{
+				"backend":                   path,
+				"namespace":                 nsClient.Namespace(),
+				"accessor":                  mount.Accessor,
+				"description":               mount.Description,
+				"default_lease_ttl_seconds": mount.Config.DefaultLeaseTTL,
+				"max_lease_ttl_seconds":     mount.Config.MaxLeaseTTL,
+				"plugin_version":            mount.PluginVersion,
+				"local":                     mount.Local,
+			})
+		}
+	}
+
+	d.SetId(strings.Trim(client.Namespace()+"/"+pluginName, "/"))
+	if err := d.Set("paths", paths); err != nil {
+		return fmt.Errorf("error setting paths: %s", err)
+	}
+	if err := d.Set("backends", backends); err != nil {
+		return fmt.Errorf("error setting backends: %s", err)
+	}
+	return nil
+}
+
+// listChildNamespaces returns the paths of every namespace below the given
+// one, relative to the client's namespace, in depth-first order. Vault
+// servers without namespaces have none.
+func listChildNamespaces(client *api.Client, namespace string) ([]string, error) {
+	nsClient := client
+	if namespace != "" {
+		nsClient = client.WithNamespace(strings.Trim(client.Namespace()+"/"+namespace, "/"))
+	}
+	namespacesPath := strings.Trim(namespace+"/sys/namespaces", "/")
+
+	log.Printf("[DEBUG] Listing %q", namespacesPath)
+	resp, err := nsClient.Logical().List("sys/namespaces")
+	if err != nil {
+		if kind := classifyVaultError(err); kind == vaultErrorNotFound || kind == vaultErrorUnsupported {
+			log.Printf("[DEBUG] %q not supported, assuming no namespaces", namespacesPath)
+			return nil, nil
+		}
+		return nil, vaultError(err, "listing", namespacesPath, "list")
+	}
+	log.Printf("[DEBUG] Listed %q", namespacesPath)
+	if resp == nil {
+		return nil, nil
+	}
+
+	keys, _ := resp.Data["keys"].([]interface{})
+	children := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if key, ok := k.(string); ok {
+			children = append(children, strings.Trim(namespace+"/"+key, "/"))
+		}
+	}
+	sort.Strings(children)
+
+	var namespaces []string
+	for _, child := range children {
+		grandchildren, err := listChildNamespaces(client, child)
+		if err != nil {
+			return nil, err
+		}
+		namespaces = append(namespaces, child)
+		namespaces = append(namespaces, grandchildren...)
+	}
+	return namespaces, nil
+}
@@ -0,0 +1,158 @@
+package vaultgrafanacloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/vault/api"
+)
+
+func TestGrafanaCloudSecretBackendsDataSource(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretBackendsDataSource_config(backend),
+				Check:  testAccGrafanaCloudSecretBackendsContains(backend),
+			},
+		},
+	})
+}
+
+func TestGrafanaCloudSecretBackendsDataSource_recursive(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	namespace := acctest.RandomWithPrefix("tf-test-ns")
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck: func() {
+			testutil.TestEntPreCheck(t)
+			testAccNamespace(t, namespace)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretBackend_namespaceConfig(namespace, backend, "key", "http://localhost", "test_org", "user") + `
+data "vaultgrafanacloud_secret_backends" "test" {
+	recursive = true
+	depends_on = [vaultgrafanacloud_secret_backend.test]
+}`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("data.vaultgrafanacloud_secret_backends.test", "paths.*", namespace+"/"+backend),
+					resource.TestCheckTypeSetElemNestedAttrs("data.vaultgrafanacloud_secret_backends.test", "backends.*", map[string]string{
+						"backend":   backend,
+						"namespace": namespace,
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestListChildNamespaces(t *testing.T) {
+	tests := map[string]struct {
+		children map[string][]string
+		want     []string
+	}{
+		"namespaces": {
+			children: map[string][]string{
+				"":  {"b/", "a/"},
+				"a": {"c/"},
+			},
+			want: []string{"a", "a/c", "b"},
+		},
+		"no namespaces": {
+			children: map[string][]string{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				keys, ok := tt.children[r.Header.Get("X-Vault-Namespace")]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"errors": []}`))
+					return
+				}
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"keys": keys}})
+			}))
+			defer server.Close()
+
+			config := api.DefaultConfig()
+			config.Address = server.URL
+			config.MaxRetries = 0
+			client, err := api.NewClient(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := listChildNamespaces(client, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected namespaces %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// testAccGrafanaCloudSecretBackendsContains checks the data source found the
+// backend, leaving room for backends mounted by other tests.
+func testAccGrafanaCloudSecretBackendsContains(backend string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["data.vaultgrafanacloud_secret_backends.test"]
+		if !ok {
+			return fmt.Errorf("data source not found in state")
+		}
+		for k, v := range rs.Primary.Attributes {
+			var i int
+			if _, err := fmt.Sscanf(k, "backends.%d.backend", &i); err != nil || v != backend {
+				continue
+			}
+			prefix := fmt.Sprintf("backends.%d.", i)
+			if rs.Primary.Attributes[prefix+"description"] != "test backend" {
+				return fmt.Errorf("expected description %q, got %q", "test backend", rs.Primary.Attributes[prefix+"description"])
+			}
+			if rs.Primary.Attributes[prefix+"accessor"] == "" {
+				return fmt.Errorf("expected an accessor for backend %q", backend)
+			}
+			return nil
+		}
+		return fmt.Errorf("backend %q not found in %v", backend, rs.Primary.Attributes)
+	}
+}
+
+func testGrafanaCloudSecretBackendsDataSource_config(backend string) string {
+	return testGrafanaCloudSecretBackendsConfig(backend) + `
+data "vaultgrafanacloud_secret_backends" "test" {
+	depends_on = [vaultgrafanacloud_secret_backend.test]
+}`
+}
+
+// testGrafanaCloudSecretBackendsConfig returns a configured backend with
+// mount tuning.
+func testGrafanaCloudSecretBackendsConfig(backend string) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+	description = "test backend"
+	default_lease_ttl_seconds = 60
+	max_lease_ttl_seconds = 120
+}
+`, backend)
+}
@@ -0,0 +1,79 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func GrafanaCloudSecretRoleDataSource() *schema.Resource {
+	return &schema.Resource{
+		Read: grafanaCloudSecretRoleDataSourceRead,
+
+		Schema: map[string]*schema.Schema{
+			"backend": {
+				Type:        schema.TypeString,
+				Default:     "grafana-cloud",
+				Optional:    true,
+				Description: "The mount path of the Grafana Cloud backend.",
+			},
+			"namespace": namespaceDataSourceSchema(),
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the role",
+			},
+			"gc_role": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Grafana Cloud role, i.e. the key authorization level",
+			},
+			"ttl": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Default lease for generated credentials, as a number of seconds",
+			},
+			"max_ttl": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Maximum time for role, as a number of seconds",
+			},
+			"ttl_seconds": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Default lease for generated credentials in seconds",
+			},
+			"max_ttl_seconds": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum time for role in seconds",
+			},
+		},
+	}
+}
+
+func grafanaCloudSecretRoleDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+	backend := normaliseBackend(d.Get("backend"))
+	rolePath := fmt.Sprintf("%s/roles/%s", backend, d.Get("name").(string))
+
+	log.Printf("[DEBUG] Reading %q", rolePath)
+	resp, err := client.Logical().Read(rolePath)
+	if err != nil {
+		return vaultError(err, "reading", rolePath, "read")
+	}
+	log.Printf("[DEBUG] Read %q", rolePath)
+	if resp == nil {
+		return fmt.Errorf("role %q not found", rolePath)
+	}
+
+	d.SetId(grafanaCloudSecretRoleID(backend, d.Get("name").(string)))
+	if err := d.Set("backend", backend); err != nil {
+		return fmt.Errorf("error setting backend: %s", err)
+	}
+	return setGrafanaCloudSecretRole(d, resp)
+}
@@ -0,0 +1,40 @@
+package vaultgrafanacloud
+
+import (
+	"testing"
+
+	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestGrafanaCloudSecretRoleDataSource(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretRoleDataSource_config(backend),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_role.test", "id", grafanaCloudSecretRoleID(backend, "viewer")),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_role.test", "backend", backend),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_role.test", "name", "viewer"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_role.test", "gc_role", "Viewer"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_role.test", "ttl", "60"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_role.test", "ttl_seconds", "60"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_role.test", "max_ttl_seconds", "120"),
+				),
+			},
+		},
+	})
+}
+
+func testGrafanaCloudSecretRoleDataSource_config(backend string) string {
+	return testGrafanaCloudSecretRolesConfig(backend) + `
+data "vaultgrafanacloud_secret_role" "test" {
+	backend = vaultgrafanacloud_secret_role.viewer.backend
+	name = vaultgrafanacloud_secret_role.viewer.name
+}`
+}
@@ -0,0 +1,128 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func GrafanaCloudSecretRolesDataSource() *schema.Resource {
+	return &schema.Resource{
+		Read: grafanaCloudSecretRolesDataSourceRead,
+
+		Schema: map[string]*schema.Schema{
+			"backend": {
+				Type:        schema.TypeString,
+				Default:     "grafana-cloud",
+				Optional:    true,
+				Description: "The mount path of the Grafana Cloud backend.",
+			},
+			"namespace": namespaceDataSourceSchema(),
+			"details": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to read each role and return its details in roles",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the roles on the backend",
+			},
+			"roles": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The roles on the backend, only read if details is set",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the role",
+						},
+						"gc_role": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The Grafana Cloud role, i.e. the key authorization level",
+						},
+						"ttl_seconds": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Default lease for generated credentials in seconds",
+						},
+						"max_ttl_seconds": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "Maximum time for role in seconds",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func grafanaCloudSecretRolesDataSourceRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+	backend := normaliseBackend(d.Get("backend"))
+	rolesPath := fmt.Sprintf("%s/roles", backend)
+
+	log.Printf("[DEBUG] Listing %q", rolesPath)
+	resp, err := client.Logical().List(rolesPath)
+	if err != nil {
+		return vaultError(err, "listing", rolesPath, "list")
+	}
+	log.Printf("[DEBUG] Listed %q", rolesPath)
+
+	names := []string{}
+	if resp != nil {
+		if keys, ok := resp.Data["keys"].([]interface{}); ok {
+			for _, k := range keys {
+				names = append(names, k.(string))
+			}
+		}
+	}
+	sort.Strings(names)
+
+	roles := []map[string]interface{}{}
+	if d.Get("details").(bool) {
+		for _, name := range names {
+			rolePath := fmt.Sprintf("%s/%s", rolesPath, name)
+			log.Printf("[DEBUG] Reading %q", rolePath)
+			resp, err := client.Logical().Read(rolePath)
+			if err != nil {
+				return vaultError(err, "reading", rolePath, "read")
+			}
+			log.Printf("[DEBUG] Read %q", rolePath)
+			if resp == nil {
+				// Deleted since it was listed
+				continue
+			}
+			role := map[string]interface{}{"name": name}
+			for _, k := range grafanaCloudSecretRoleFields {
+				if val, ok := resp.Data[k]; ok {
+					role[k] = val
+				}
+			}
+			roles = append(roles, role)
+		}
+	}
+
+	d.SetId(rolesPath)
+	if err := d.Set("backend", backend); err != nil {
+		return fmt.Errorf("error setting backend: %s", err)
+	}
+	if err := d.Set("names", names); err != nil {
+		return fmt.Errorf("error setting names: %s", err)
+	}
+	if err := d.Set("roles", roles); err != nil {
+		return fmt.Errorf("error setting roles: %s", err)
+	}
+	return nil
+}
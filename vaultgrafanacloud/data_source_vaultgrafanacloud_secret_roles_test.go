@@ -0,0 +1,83 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestGrafanaCloudSecretRolesDataSource(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretRolesDataSource_config(backend, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_roles.test", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_roles.test", "names.0", "admin"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_roles.test", "names.1", "viewer"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_roles.test", "roles.#", "0"),
+				),
+			},
+			{
+				Config: testGrafanaCloudSecretRolesDataSource_config(backend, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_roles.test", "names.#", "2"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_roles.test", "roles.#", "2"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_roles.test", "roles.0.name", "admin"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_roles.test", "roles.0.gc_role", "Admin"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_roles.test", "roles.1.name", "viewer"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_roles.test", "roles.1.ttl_seconds", "60"),
+					resource.TestCheckResourceAttr("data.vaultgrafanacloud_secret_roles.test", "roles.1.max_ttl_seconds", "120"),
+				),
+			},
+		},
+	})
+}
+
+func testGrafanaCloudSecretRolesDataSource_config(backend string, details bool) string {
+	return testGrafanaCloudSecretRolesConfig(backend) + fmt.Sprintf(`
+data "vaultgrafanacloud_secret_roles" "test" {
+	backend = vaultgrafanacloud_secret_backend.test.backend
+	details = %t
+
+	depends_on = [
+		vaultgrafanacloud_secret_role.viewer,
+		vaultgrafanacloud_secret_role.admin,
+	]
+}`, details)
+}
+
+// testGrafanaCloudSecretRolesConfig returns a backend with a viewer and an
+// admin role.
+func testGrafanaCloudSecretRolesConfig(backend string) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+}
+
+resource "vaultgrafanacloud_secret_role" "viewer" {
+	backend = vaultgrafanacloud_secret_backend.test.backend
+	name = "viewer"
+	gc_role = "Viewer"
+	ttl_seconds = 60
+	max_ttl_seconds = 120
+}
+
+resource "vaultgrafanacloud_secret_role" "admin" {
+	backend = vaultgrafanacloud_secret_backend.test.backend
+	name = "admin"
+	gc_role = "Admin"
+}
+`, backend)
+}
@@ -0,0 +1,240 @@
+package vaultgrafanacloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/vault/api"
+)
+
+// grafanaCloudCredentialsPrivateKey is the key of the lease in the private
+// data Terraform keeps for an open vaultgrafanacloud_credentials ephemeral
+// resource.
+const grafanaCloudCredentialsPrivateKey = "lease"
+
+// grafanaCloudCredentialsPrivate is the private data Terraform keeps for an
+// open vaultgrafanacloud_credentials ephemeral resource.
+type grafanaCloudCredentialsPrivate struct {
+	LeaseID   string `json:"lease_id"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type grafanaCloudCredentialsModel struct {
+	Backend        types.String `tfsdk:"backend"`
+	Namespace      types.String `tfsdk:"namespace"`
+	Role           types.String `tfsdk:"role"`
+	Key            types.String `tfsdk:"key"`
+	User           types.String `tfsdk:"user"`
+	LeaseID        types.String `tfsdk:"lease_id"`
+	LeaseDuration  types.Int64  `tfsdk:"lease_duration"`
+	LeaseRenewable types.Bool   `tfsdk:"lease_renewable"`
+}
+
+type grafanaCloudCredentialsEphemeralResource struct {
+	meta *providerMeta
+}
+
+var (
+	_ ephemeral.EphemeralResourceWithConfigure = &grafanaCloudCredentialsEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithRenew     = &grafanaCloudCredentialsEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithClose     = &grafanaCloudCredentialsEphemeralResource{}
+)
+
+func NewGrafanaCloudCredentialsEphemeralResource() ephemeral.EphemeralResource {
+	return &grafanaCloudCredentialsEphemeralResource{}
+}
+
+func (r *grafanaCloudCredentialsEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_credentials"
+}
+
+func (r *grafanaCloudCredentialsEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"backend": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "The mount path of the Grafana Cloud backend. Defaults to grafana-cloud.",
+			},
+			"namespace": schema.StringAttribute{
+				Optional:    true,
+				Description: "The Vault Enterprise namespace to issue the credentials in, overriding the provider's namespace.",
+			},
+			"role": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the role to issue credentials for",
+			},
+			"key": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The issued Grafana Cloud API key",
+			},
+			"user": schema.StringAttribute{
+				Computed:    true,
+				Description: "The User configured on the backend, needed to interact with prometheus",
+			},
+			"lease_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The lease ID of the issued credentials",
+			},
+			"lease_duration": schema.Int64Attribute{
+				Computed:    true,
+				Description: "The lease duration of the issued credentials in seconds",
+			},
+			"lease_renewable": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the lease of the issued credentials is renewable",
+			},
+		},
+	}
+}
+
+func (r *grafanaCloudCredentialsEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	meta, ok := req.ProviderData.(*providerMeta)
+	if !ok {
+		resp.Diagnostics.AddError("Error configuring vaultgrafanacloud_credentials", fmt.Sprintf("unexpected provider data %T", req.ProviderData))
+		return
+	}
+	r.meta = meta
+}
+
+func (r *grafanaCloudCredentialsEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data grafanaCloudCredentialsModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := r.namespaceClient(data.Namespace.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error issuing credentials", err.Error())
+		return
+	}
+	backend := data.Backend.ValueString()
+	if backend == "" {
+		backend = "grafana-cloud"
+	}
+	backend = normaliseBackend(backend)
+	credsPath := fmt.Sprintf("%s/creds/%s", backend, data.Role.ValueString())
+
+	log.Printf("[DEBUG] Issuing credentials from %q", credsPath)
+	secret, err := client.Logical().Read(credsPath)
+	if err != nil {
+		resp.Diagnostics.AddError("Error issuing credentials", vaultError(err, "reading", credsPath, "read").Error())
+		return
+	}
+	log.Printf("[DEBUG] Issued credentials from %q", credsPath)
+	if secret == nil {
+		resp.Diagnostics.AddError("Error issuing credentials", fmt.Sprintf("no credentials returned by %q", credsPath))
+		return
+	}
+
+	private, err := json.Marshal(grafanaCloudCredentialsPrivate{
+		LeaseID:   secret.LeaseID,
+		Namespace: data.Namespace.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error issuing credentials", fmt.Sprintf("error encoding lease %q: %s", secret.LeaseID, err))
+		return
+	}
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, grafanaCloudCredentialsPrivateKey, private)...)
+
+	data.Backend = types.StringValue(backend)
+	data.LeaseID = types.StringValue(secret.LeaseID)
+	data.LeaseDuration = types.Int64Value(int64(secret.LeaseDuration))
+	data.LeaseRenewable = types.BoolValue(secret.Renewable)
+	data.Key = types.StringNull()
+	if v, ok := secret.Data["key"].(string); ok {
+		data.Key = types.StringValue(v)
+	}
+	data.User = types.StringNull()
+	if v, ok := secret.Data["user"].(string); ok {
+		data.User = types.StringValue(v)
+	}
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+	resp.RenewAt = grafanaCloudCredentialsRenewAt(secret.LeaseDuration, secret.Renewable)
+}
+
+func (r *grafanaCloudCredentialsEphemeralResource) Renew(ctx context.Context, req ephemeral.RenewRequest, resp *ephemeral.RenewResponse) {
+	lease, client, err := r.lease(ctx, req.Private)
+	if err != nil {
+		resp.Diagnostics.AddError("Error renewing credentials", err.Error())
+		return
+	}
+
+	log.Printf("[DEBUG] Renewing lease %q", lease.LeaseID)
+	secret, err := client.Sys().Renew(lease.LeaseID, 0)
+	if err != nil {
+		resp.Diagnostics.AddError("Error renewing credentials", vaultError(err, fmt.Sprintf("renewing lease %q at", lease.LeaseID), "sys/leases/renew", "update").Error())
+		return
+	}
+	log.Printf("[DEBUG] Renewed lease %q", lease.LeaseID)
+	if secret != nil {
+		resp.RenewAt = grafanaCloudCredentialsRenewAt(secret.LeaseDuration, secret.Renewable)
+	}
+}
+
+func (r *grafanaCloudCredentialsEphemeralResource) Close(ctx context.Context, req ephemeral.CloseRequest, resp *ephemeral.CloseResponse) {
+	lease, client, err := r.lease(ctx, req.Private)
+	if err != nil {
+		resp.Diagnostics.AddError("Error revoking credentials", err.Error())
+		return
+	}
+
+	log.Printf("[DEBUG] Revoking lease %q", lease.LeaseID)
+	if err := client.Sys().Revoke(lease.LeaseID); err != nil && !isNotFound(err) {
+		resp.Diagnostics.AddError("Error revoking credentials", vaultError(err, fmt.Sprintf("revoking lease %q at", lease.LeaseID), "sys/leases/revoke", "update").Error())
+		return
+	}
+	log.Printf("[DEBUG] Revoked lease %q", lease.LeaseID)
+}
+
+// privateData is satisfied by the private data Terraform passes to Renew and
+// Close.
+type privateData interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// lease decodes the lease from the private data, returning it along with a
+// client scoped to its namespace.
+func (r *grafanaCloudCredentialsEphemeralResource) lease(ctx context.Context, private privateData) (*grafanaCloudCredentialsPrivate, *api.Client, error) {
+	data, diags := private.GetKey(ctx, grafanaCloudCredentialsPrivateKey)
+	if diags.HasError() {
+		return nil, nil, fmt.Errorf("error reading lease: %s", diags[0].Detail())
+	}
+	lease := &grafanaCloudCredentialsPrivate{}
+	if err := json.Unmarshal(data, lease); err != nil {
+		return nil, nil, fmt.Errorf("error decoding lease: %s", err)
+	}
+	client, err := r.namespaceClient(lease.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lease, client, nil
+}
+
+func (r *grafanaCloudCredentialsEphemeralResource) namespaceClient(namespace string) (*api.Client, error) {
+	if r.meta == nil {
+		return nil, fmt.Errorf("the provider has not been configured")
+	}
+	return getNamespaceClient(namespace, r.meta)
+}
+
+// grafanaCloudCredentialsRenewAt renews a lease once two thirds of its
+// duration have passed, leaving Terraform time to renew it before it expires.
+func grafanaCloudCredentialsRenewAt(leaseDuration int, renewable bool) time.Time {
+	if !renewable || leaseDuration <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(leaseDuration) * time.Second * 2 / 3)
+}
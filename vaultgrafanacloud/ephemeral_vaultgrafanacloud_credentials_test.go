@@ -0,0 +1,216 @@
+package vaultgrafanacloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/vault/api"
+)
+
+func TestGrafanaCloudCredentialsEphemeralResource(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	testutil.SkipTestAcc(t)
+	grafanaCloud := testutil.TestCredsPreCheck(t)
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudCredentialsConfig(backend, grafanaCloud),
+				Check:  testAccGrafanaCloudCredentialsEphemeralLifecycle(backend, grafanaCloud[3]),
+			},
+		},
+	})
+}
+
+func TestGrafanaCloudCredentialsEphemeralResource_lifecycle(t *testing.T) {
+	const leaseID = "grafana-cloud/creds/test/abc"
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, r.Header.Get("X-Vault-Namespace")))
+		switch r.URL.Path {
+		case "/v1/grafana-cloud/creds/test":
+			_, _ = w.Write([]byte(`{"lease_id": "` + leaseID + `", "lease_duration": 300, "renewable": true, "data": {"key": "secret", "user": "123"}}`))
+		case "/v1/sys/leases/renew":
+			_, _ = w.Write([]byte(`{"lease_id": "` + leaseID + `", "lease_duration": 300, "renewable": true}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	config.MaxRetries = 0
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Provider()
+	p.SetMeta(testProviderMeta(client))
+
+	attrs, err := testGrafanaCloudCredentialsEphemeralLifecycle(p, map[string]tftypes.Value{
+		"namespace": tftypes.NewValue(tftypes.String, "ns1"),
+		"role":      tftypes.NewValue(tftypes.String, "test"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]tftypes.Value{
+		"backend":         tftypes.NewValue(tftypes.String, "grafana-cloud"),
+		"namespace":       tftypes.NewValue(tftypes.String, "ns1"),
+		"role":            tftypes.NewValue(tftypes.String, "test"),
+		"key":             tftypes.NewValue(tftypes.String, "secret"),
+		"user":            tftypes.NewValue(tftypes.String, "123"),
+		"lease_id":        tftypes.NewValue(tftypes.String, leaseID),
+		"lease_duration":  tftypes.NewValue(tftypes.Number, 300),
+		"lease_renewable": tftypes.NewValue(tftypes.Bool, true),
+	}
+	for name, v := range expected {
+		if !attrs[name].Equal(v) {
+			t.Errorf("expected %s to be %s, got %s", name, v, attrs[name])
+		}
+	}
+
+	expectedRequests := []string{
+		"GET /v1/grafana-cloud/creds/test ns1",
+		"PUT /v1/sys/leases/renew ns1",
+		"PUT /v1/sys/leases/revoke ns1",
+	}
+	if !reflect.DeepEqual(requests, expectedRequests) {
+		t.Errorf("expected requests %v, got %v", expectedRequests, requests)
+	}
+}
+
+// testAccGrafanaCloudCredentialsEphemeralLifecycle checks that the ephemeral
+// resource issues credentials for the backend's user and revokes them once
+// it is closed.
+func testAccGrafanaCloudCredentialsEphemeralLifecycle(backend, user string) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		attrs, err := testGrafanaCloudCredentialsEphemeralLifecycle(testProvider, map[string]tftypes.Value{
+			"backend": tftypes.NewValue(tftypes.String, backend),
+			"role":    tftypes.NewValue(tftypes.String, "test"),
+		})
+		if err != nil {
+			return err
+		}
+
+		var key, leaseID, resultUser string
+		for name, v := range map[string]*string{"key": &key, "lease_id": &leaseID, "user": &resultUser} {
+			if err := attrs[name].As(v); err != nil {
+				return err
+			}
+		}
+		if key == "" {
+			return fmt.Errorf("expected a key to be issued")
+		}
+		if resultUser != user {
+			return fmt.Errorf("expected user %q, got %q", user, resultUser)
+		}
+
+		client := testProvider.Meta().(*providerMeta).client
+		if _, err := client.Sys().Lookup(leaseID); err == nil {
+			return fmt.Errorf("expected lease %q to be revoked", leaseID)
+		}
+		return nil
+	}
+}
+
+// testGrafanaCloudCredentialsEphemeralLifecycle opens, renews and closes the
+// ephemeral resource through the framework provider's gRPC server, the way
+// Terraform would, with the client of the configured SDK provider. It returns
+// the opened resource's attributes.
+func testGrafanaCloudCredentialsEphemeralLifecycle(p *schema.Provider, config map[string]tftypes.Value) (map[string]tftypes.Value, error) {
+	ctx := context.Background()
+	s := providerserver.NewProtocol5(newFrameworkProvider(p))()
+
+	schemas, err := s.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		return nil, err
+	}
+	providerType := schemas.Provider.ValueType()
+	providerConfig, err := tfprotov5.NewDynamicValue(providerType, tftypes.NewValue(providerType, nil))
+	if err != nil {
+		return nil, err
+	}
+	configured, err := s.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: &providerConfig})
+	if err != nil {
+		return nil, err
+	}
+	if len(configured.Diagnostics) > 0 {
+		return nil, fmt.Errorf("error configuring: %s", configured.Diagnostics[0].Detail)
+	}
+
+	typ := schemas.EphemeralResourceSchemas["vaultgrafanacloud_credentials"].ValueType().(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attrType := range typ.AttributeTypes {
+		if v, ok := config[name]; ok {
+			values[name] = v
+		} else {
+			values[name] = tftypes.NewValue(attrType, nil)
+		}
+	}
+	resourceConfig, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, values))
+	if err != nil {
+		return nil, err
+	}
+
+	opened, err := s.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "vaultgrafanacloud_credentials",
+		Config:   &resourceConfig,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(opened.Diagnostics) > 0 {
+		return nil, fmt.Errorf("error opening: %s", opened.Diagnostics[0].Detail)
+	}
+	if opened.RenewAt.IsZero() {
+		return nil, fmt.Errorf("expected the renewable lease to be renewed")
+	}
+	result, err := opened.Result.Unmarshal(typ)
+	if err != nil {
+		return nil, err
+	}
+	var attrs map[string]tftypes.Value
+	if err := result.As(&attrs); err != nil {
+		return nil, err
+	}
+
+	renewed, err := s.RenewEphemeralResource(ctx, &tfprotov5.RenewEphemeralResourceRequest{
+		TypeName: "vaultgrafanacloud_credentials",
+		Private:  opened.Private,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(renewed.Diagnostics) > 0 {
+		return nil, fmt.Errorf("error renewing: %s", renewed.Diagnostics[0].Detail)
+	}
+
+	closed, err := s.CloseEphemeralResource(ctx, &tfprotov5.CloseEphemeralResourceRequest{
+		TypeName: "vaultgrafanacloud_credentials",
+		Private:  renewed.Private,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(closed.Diagnostics) > 0 {
+		return nil, fmt.Errorf("error closing: %s", closed.Diagnostics[0].Detail)
+	}
+	return attrs, nil
+}
@@ -0,0 +1,137 @@
+package vaultgrafanacloud
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// vaultErrorKind classifies the errors returned by Vault.
+type vaultErrorKind int
+
+const (
+	vaultErrorUnknown vaultErrorKind = iota
+	vaultErrorNotFound
+	vaultErrorPermissionDenied
+	vaultErrorConflict
+	vaultErrorRetryable
+	vaultErrorSealed
+	vaultErrorUnsupported
+)
+
+func (k vaultErrorKind) String() string {
+	switch k {
+	case vaultErrorNotFound:
+		return "not found"
+	case vaultErrorPermissionDenied:
+		return "permission denied"
+	case vaultErrorConflict:
+		return "conflict"
+	case vaultErrorRetryable:
+		return "retryable"
+	case vaultErrorSealed:
+		return "sealed"
+	case vaultErrorUnsupported:
+		return "unsupported"
+	default:
+		return "unknown"
+	}
+}
+
+// Vault reports some missing objects, conflicts and unsupported operations
+// with a 400 status, so these are told apart by their message.
+var (
+	vaultNotFoundMessages = []string{
+		"invalid lease",
+		"lease not found",
+		"lease expired",
+		"no matching mount",
+		"no secret engine mount",
+	}
+	vaultConflictMessages = []string{
+		"path is already in use",
+		"existing mount at",
+	}
+	vaultUnsupportedMessages = []string{
+		"unsupported operation",
+	}
+)
+
+// classifyVaultError classifies an error returned by the Vault client by its
+// status code and, where the status code is ambiguous, its message.
+func classifyVaultError(err error) vaultErrorKind {
+	if err == nil {
+		return vaultErrorUnknown
+	}
+
+	var respErr *api.ResponseError
+	if !errors.As(err, &respErr) {
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			return vaultErrorRetryable
+		}
+		return vaultErrorUnknown
+	}
+
+	msg := strings.ToLower(strings.Join(respErr.Errors, "; "))
+	switch respErr.StatusCode {
+	case http.StatusNotFound:
+		return vaultErrorNotFound
+	case http.StatusForbidden:
+		return vaultErrorPermissionDenied
+	case http.StatusConflict:
+		return vaultErrorConflict
+	case http.StatusMethodNotAllowed:
+		return vaultErrorUnsupported
+	case http.StatusServiceUnavailable:
+		if strings.Contains(msg, "vault is sealed") {
+			return vaultErrorSealed
+		}
+		return vaultErrorRetryable
+	case http.StatusPreconditionFailed, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusGatewayTimeout:
+		return vaultErrorRetryable
+	case http.StatusBadRequest:
+		for _, m := range vaultNotFoundMessages {
+			if strings.Contains(msg, m) {
+				return vaultErrorNotFound
+			}
+		}
+		for _, m := range vaultConflictMessages {
+			if strings.Contains(msg, m) {
+				return vaultErrorConflict
+			}
+		}
+		for _, m := range vaultUnsupportedMessages {
+			if strings.Contains(msg, m) {
+				return vaultErrorUnsupported
+			}
+		}
+	}
+	return vaultErrorUnknown
+}
+
+// isNotFound reports whether err is Vault reporting a missing object.
+func isNotFound(err error) bool {
+	return classifyVaultError(err) == vaultErrorNotFound
+}
+
+// vaultError describes err, returned while action-ing path, e.g. "reading".
+// When Vault denied the request it names the capability the token needs on
+// path.
+func vaultError(err error, action, path, capability string) error {
+	switch classifyVaultError(err) {
+	case vaultErrorPermissionDenied:
+		return fmt.Errorf("error %s %q: permission denied, the Vault token needs the %s capability on %q: %w", action, path, capability, path, err)
+	case vaultErrorSealed:
+		return fmt.Errorf("error %s %q: Vault is sealed: %w", action, path, err)
+	case vaultErrorConflict:
+		return fmt.Errorf("error %s %q: conflicts with an existing object: %w", action, path, err)
+	default:
+		return fmt.Errorf("error %s %q: %w", action, path, err)
+	}
+}
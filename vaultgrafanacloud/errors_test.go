@@ -0,0 +1,135 @@
+package vaultgrafanacloud
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+func TestClassifyVaultError(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want vaultErrorKind
+	}{
+		"nil": {
+			want: vaultErrorUnknown,
+		},
+		"not found": {
+			err:  &api.ResponseError{StatusCode: 404},
+			want: vaultErrorNotFound,
+		},
+		"invalid lease": {
+			err:  &api.ResponseError{StatusCode: 400, Errors: []string{"invalid lease"}},
+			want: vaultErrorNotFound,
+		},
+		"wrapped not found": {
+			err:  fmt.Errorf("error reading: %w", &api.ResponseError{StatusCode: 404}),
+			want: vaultErrorNotFound,
+		},
+		"permission denied": {
+			err:  &api.ResponseError{StatusCode: 403, Errors: []string{"1 error occurred:\n\t* permission denied\n\n"}},
+			want: vaultErrorPermissionDenied,
+		},
+		"path in use": {
+			err:  &api.ResponseError{StatusCode: 400, Errors: []string{"path is already in use at grafana-cloud/"}},
+			want: vaultErrorConflict,
+		},
+		"sealed": {
+			err:  &api.ResponseError{StatusCode: 503, Errors: []string{"Vault is sealed"}},
+			want: vaultErrorSealed,
+		},
+		"unavailable": {
+			err:  &api.ResponseError{StatusCode: 503},
+			want: vaultErrorRetryable,
+		},
+		"rate limited": {
+			err:  &api.ResponseError{StatusCode: 429},
+			want: vaultErrorRetryable,
+		},
+		"network": {
+			err:  &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			want: vaultErrorRetryable,
+		},
+		"method not allowed": {
+			err:  &api.ResponseError{StatusCode: 405, Errors: []string{"1 error occurred:\n\t* unsupported operation\n\n"}},
+			want: vaultErrorUnsupported,
+		},
+		"unsupported operation": {
+			err:  &api.ResponseError{StatusCode: 400, Errors: []string{"unsupported operation"}},
+			want: vaultErrorUnsupported,
+		},
+		"bad request": {
+			err:  &api.ResponseError{StatusCode: 400, Errors: []string{"missing gc_role"}},
+			want: vaultErrorUnknown,
+		},
+		"other": {
+			err:  errors.New("boom"),
+			want: vaultErrorUnknown,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := classifyVaultError(tt.err); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestVaultError(t *testing.T) {
+	respErr := &api.ResponseError{StatusCode: 403, Errors: []string{"permission denied"}}
+	err := vaultError(respErr, "reading", "grafana-cloud/config", "read")
+
+	if !strings.Contains(err.Error(), `the Vault token needs the read capability on "grafana-cloud/config"`) {
+		t.Errorf("expected the error to name the path and capability, got %q", err)
+	}
+	if !errors.Is(err, respErr) {
+		t.Errorf("expected the error to wrap %v", respErr)
+	}
+
+	err = vaultError(errors.New("boom"), "reading", "grafana-cloud/config", "read")
+	if err.Error() != `error reading "grafana-cloud/config": boom` {
+		t.Errorf("unexpected error %q", err)
+	}
+}
+
+func TestDeleteMissing(t *testing.T) {
+	testutil.SkipTestAcc(t)
+	testutil.TestAccPreCheck(t)
+	client := testAccClient(t)
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+
+	tests := map[string]struct {
+		resource *schema.Resource
+		id       string
+	}{
+		"backend": {
+			resource: GrafanaCloudSecretBackendResource(),
+			id:       backend,
+		},
+		"role": {
+			resource: GrafanaCloudSecretRoleResource(),
+			id:       backend + "/roles/missing",
+		},
+		"credential": {
+			resource: GrafanaCloudCredentialResource(),
+			id:       backend + "/creds/missing/0123456789",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := tt.resource.TestResourceData()
+			d.SetId(tt.id)
+			if err := tt.resource.Delete(d, testProviderMeta(client)); err != nil {
+				t.Errorf("expected deleting a missing %s to succeed, got %s", name, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package vaultgrafanacloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// frameworkProvider serves the ephemeral resources, which the SDK cannot. It
+// is muxed with the SDK provider, which configures the Vault client both
+// providers use.
+type frameworkProvider struct {
+	sdk *schema.Provider
+}
+
+var _ provider.ProviderWithEphemeralResources = &frameworkProvider{}
+
+func newFrameworkProvider(sdk *schema.Provider) provider.Provider {
+	return &frameworkProvider{sdk: sdk}
+}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "vaultgrafanacloud"
+}
+
+// Schema mirrors the SDK provider's schema, as the muxed providers must
+// share one.
+func (p *frameworkProvider) Schema(ctx context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	sdkSchema, err := schema.NewGRPCProviderServer(p.sdk).GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading the provider schema", err.Error())
+		return
+	}
+	attributes, blocks, err := frameworkProviderSchema(sdkSchema.Provider.Block)
+	if err != nil {
+		resp.Diagnostics.AddError("Error converting the provider schema", err.Error())
+		return
+	}
+	resp.Schema = fwschema.Schema{
+		Attributes: attributes,
+		Blocks:     blocks,
+	}
+}
+
+// Configure hands the ephemeral resources the SDK provider's meta. The muxed
+// server configures the SDK provider first.
+func (p *frameworkProvider) Configure(_ context.Context, _ provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	meta := p.sdk.Meta()
+	if meta == nil {
+		resp.Diagnostics.AddError("Error configuring the provider", "the Vault client has not been configured")
+		return
+	}
+	resp.EphemeralResourceData = meta
+}
+
+func (p *frameworkProvider) EphemeralResources(context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewGrafanaCloudCredentialsEphemeralResource,
+	}
+}
+
+func (p *frameworkProvider) Resources(context.Context) []func() resource.Resource {
+	return nil
+}
+
+func (p *frameworkProvider) DataSources(context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+// frameworkProviderSchema converts the attributes and nested blocks of the
+// SDK provider's schema.
+func frameworkProviderSchema(b *tfprotov5.SchemaBlock) (map[string]fwschema.Attribute, map[string]fwschema.Block, error) {
+	attributes := make(map[string]fwschema.Attribute, len(b.Attributes))
+	for _, a := range b.Attributes {
+		switch {
+		case a.Type.Is(tftypes.String):
+			attributes[a.Name] = fwschema.StringAttribute{
+				Required:    a.Required,
+				Optional:    a.Optional,
+				Sensitive:   a.Sensitive,
+				Description: a.Description,
+			}
+		case a.Type.Is(tftypes.Bool):
+			attributes[a.Name] = fwschema.BoolAttribute{
+				Required:    a.Required,
+				Optional:    a.Optional,
+				Sensitive:   a.Sensitive,
+				Description: a.Description,
+			}
+		case a.Type.Is(tftypes.Number):
+			attributes[a.Name] = fwschema.NumberAttribute{
+				Required:    a.Required,
+				Optional:    a.Optional,
+				Sensitive:   a.Sensitive,
+				Description: a.Description,
+			}
+		default:
+			return nil, nil, fmt.Errorf("unsupported type %s for %s", a.Type, a.Name)
+		}
+	}
+
+	blocks := make(map[string]fwschema.Block, len(b.BlockTypes))
+	for _, nb := range b.BlockTypes {
+		if nb.Nesting != tfprotov5.SchemaNestedBlockNestingModeList {
+			return nil, nil, fmt.Errorf("unsupported nesting mode %s for %s", nb.Nesting, nb.TypeName)
+		}
+		nestedAttributes, nestedBlocks, err := frameworkProviderSchema(nb.Block)
+		if err != nil {
+			return nil, nil, err
+		}
+		blocks[nb.TypeName] = fwschema.ListNestedBlock{
+			NestedObject: fwschema.NestedBlockObject{
+				Attributes: nestedAttributes,
+				Blocks:     nestedBlocks,
+			},
+			Description: nb.Block.Description,
+		}
+	}
+	return attributes, blocks, nil
+}
@@ -0,0 +1,86 @@
+package vaultgrafanacloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// planWarnings returns, for each resource type that has them, a function
+// describing the warnings about a planned state. The SDK's CustomizeDiff can
+// only fail a plan, so warnings are added to the plan here instead.
+func planWarnings() map[string]func(planned map[string]cty.Value) []string {
+	return map[string]func(map[string]cty.Value) []string{
+		"vaultgrafanacloud_secret_role": grafanaCloudSecretRolePlanWarnings,
+	}
+}
+
+// ProviderServer serves the SDK provider's resources and data sources muxed
+// with the framework provider's ephemeral resources.
+func ProviderServer(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	return newProviderServer(ctx, Provider())
+}
+
+func newProviderServer(ctx context.Context, p *schema.Provider) (func() tfprotov5.ProviderServer, error) {
+	// the SDK provider is listed first, so it is configured before the
+	// framework provider that uses its client
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		func() tfprotov5.ProviderServer { return newGRPCProviderServer(p) },
+		providerserver.NewProtocol5(newFrameworkProvider(p)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error muxing the provider servers: %s", err)
+	}
+	return muxServer.ProviderServer, nil
+}
+
+func newGRPCProviderServer(p *schema.Provider) *grpcProviderServer {
+	return &grpcProviderServer{
+		GRPCProviderServer: schema.NewGRPCProviderServer(p),
+		provider:           p,
+		planWarnings:       planWarnings(),
+	}
+}
+
+// grpcProviderServer adds the plan warnings to the SDK provider's server.
+type grpcProviderServer struct {
+	*schema.GRPCProviderServer
+
+	provider     *schema.Provider
+	planWarnings map[string]func(planned map[string]cty.Value) []string
+}
+
+func (s *grpcProviderServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	resp, err := s.GRPCProviderServer.PlanResourceChange(ctx, req)
+	warnings, ok := s.planWarnings[req.TypeName]
+	if err != nil || !ok || resp.PlannedState == nil {
+		return resp, err
+	}
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			return resp, nil
+		}
+	}
+
+	planned, err := msgpack.Unmarshal(resp.PlannedState.MsgPack, s.provider.ResourcesMap[req.TypeName].CoreConfigSchema().ImpliedType())
+	if err != nil {
+		return nil, fmt.Errorf("error decoding planned %s: %s", req.TypeName, err)
+	}
+	// a resource being destroyed has a null planned state
+	if planned.IsNull() || !planned.IsKnown() {
+		return resp, nil
+	}
+	for _, w := range warnings(planned.AsValueMap()) {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityWarning,
+			Summary:  w,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,220 @@
+package vaultgrafanacloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/vault/api"
+)
+
+func TestProviderServer(t *testing.T) {
+	ctx := context.Background()
+	providerServer, err := newProviderServer(ctx, Provider())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := providerServer()
+
+	metadata, err := s.GetMetadata(ctx, &tfprotov5.GetMetadataRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metadata.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %s: %s", metadata.Diagnostics[0].Summary, metadata.Diagnostics[0].Detail)
+	}
+	if len(metadata.EphemeralResources) != 1 || metadata.EphemeralResources[0].TypeName != "vaultgrafanacloud_credentials" {
+		t.Fatalf("expected the vaultgrafanacloud_credentials ephemeral resource, got %v", metadata.EphemeralResources)
+	}
+	if len(metadata.Resources) == 0 || len(metadata.DataSources) == 0 {
+		t.Fatalf("expected the SDK resources and data sources to be served")
+	}
+
+	// the muxed server fails unless both providers have the same schema
+	schemas, err := s.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schemas.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %s: %s", schemas.Diagnostics[0].Summary, schemas.Diagnostics[0].Detail)
+	}
+	if _, ok := schemas.EphemeralResourceSchemas["vaultgrafanacloud_credentials"]; !ok {
+		t.Fatalf("expected a schema for the vaultgrafanacloud_credentials ephemeral resource")
+	}
+	if _, ok := schemas.ResourceSchemas["vaultgrafanacloud_credential"]; !ok {
+		t.Fatalf("expected a schema for the vaultgrafanacloud_credential resource")
+	}
+}
+
+func TestProviderServer_openUnconfigured(t *testing.T) {
+	ctx := context.Background()
+	providerServer, err := newProviderServer(ctx, Provider())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := providerServer()
+
+	schemas, err := s.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := testDynamicValue(t, schemas.EphemeralResourceSchemas["vaultgrafanacloud_credentials"], map[string]tftypes.Value{
+		"role": tftypes.NewValue(tftypes.String, "test"),
+	})
+	resp, err := s.OpenEphemeralResource(ctx, &tfprotov5.OpenEphemeralResourceRequest{
+		TypeName: "vaultgrafanacloud_credentials",
+		Config:   config,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.Diagnostics[0].Severity != tfprotov5.DiagnosticSeverityError {
+		t.Fatalf("expected an error opening the resource before the provider is configured, got %v", resp.Diagnostics)
+	}
+}
+
+func TestGRPCProviderServer_planWarnings(t *testing.T) {
+	var tuneReads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/sys/mounts/grafana-cloud/tune" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		tuneReads++
+		_, _ = w.Write([]byte(`{"default_lease_ttl": 600, "max_lease_ttl": 3600}`))
+	}))
+	defer server.Close()
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	config.MaxRetries = 0
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Provider()
+	p.SetMeta(testProviderMeta(client))
+	s := newGRPCProviderServer(p)
+	typ := p.ResourcesMap["vaultgrafanacloud_secret_role"].CoreConfigSchema().ImpliedType()
+
+	tests := map[string]struct {
+		ttl, maxTTL  string
+		strict       bool
+		wantWarnings []string
+		wantError    string
+	}{
+		"within limits": {ttl: "300", maxTTL: "600"},
+		"max capped": {
+			ttl: "300", maxTTL: "7200",
+			wantWarnings: []string{"max_ttl (7200s) exceeds the max lease TTL"},
+		},
+		"max capped strictly": {
+			ttl: "300", maxTTL: "7200", strict: true,
+			wantError: "set strict_ttls to false to only warn",
+		},
+		"mount default": {
+			ttl: "0", maxTTL: "300", strict: true,
+			wantWarnings: []string{"falls back to the default lease TTL of mount \"grafana-cloud\" and Vault issues credentials for 300s"},
+		},
+		"above mount default": {
+			ttl: "1800", maxTTL: "1800",
+			wantWarnings: []string{
+				"ttl (1800s) exceeds the default lease TTL of mount \"grafana-cloud\" (600s)",
+				"max_ttl (1800s) exceeds the default lease TTL of mount \"grafana-cloud\" (600s)",
+			},
+		},
+		"above mount default strictly": {
+			ttl: "1800", maxTTL: "1800", strict: true,
+			wantError: "ttl (1800s) exceeds the default lease TTL",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			attrs := map[string]cty.Value{}
+			for attr, attrType := range typ.AttributeTypes() {
+				attrs[attr] = cty.NullVal(attrType)
+			}
+			attrs["backend"] = cty.StringVal("grafana-cloud")
+			attrs["name"] = cty.StringVal("test")
+			attrs["gc_role"] = cty.StringVal("Viewer")
+			attrs["ttl"] = cty.StringVal(tt.ttl)
+			attrs["max_ttl"] = cty.StringVal(tt.maxTTL)
+			attrs["strict_ttls"] = cty.BoolVal(tt.strict)
+			config, err := msgpack.Marshal(cty.ObjectVal(attrs), typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			prior, err := msgpack.Marshal(cty.NullVal(typ), typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			tuneReads = 0
+			resp, err := s.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+				TypeName:         "vaultgrafanacloud_secret_role",
+				PriorState:       &tfprotov5.DynamicValue{MsgPack: prior},
+				ProposedNewState: &tfprotov5.DynamicValue{MsgPack: config},
+				Config:           &tfprotov5.DynamicValue{MsgPack: config},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tuneReads != 1 {
+				t.Errorf("expected the mount's tuning to be read once, got %d reads", tuneReads)
+			}
+
+			var warnings, errors []string
+			for _, d := range resp.Diagnostics {
+				if d.Severity == tfprotov5.DiagnosticSeverityError {
+					errors = append(errors, d.Summary)
+				} else {
+					warnings = append(warnings, d.Summary)
+				}
+			}
+			if tt.wantError != "" {
+				if len(errors) != 1 || !strings.Contains(errors[0], tt.wantError) {
+					t.Fatalf("expected an error containing %q, got %v", tt.wantError, errors)
+				}
+				return
+			}
+			if len(errors) > 0 {
+				t.Fatalf("unexpected errors: %v", errors)
+			}
+			if len(warnings) != len(tt.wantWarnings) {
+				t.Fatalf("expected warnings %v, got %v", tt.wantWarnings, warnings)
+			}
+			for i, w := range tt.wantWarnings {
+				if !strings.Contains(warnings[i], w) {
+					t.Errorf("expected a warning containing %q, got %q", w, warnings[i])
+				}
+			}
+		})
+	}
+}
+
+// testDynamicValue encodes a value of the schema's type, leaving any
+// attributes missing from attrs null.
+func testDynamicValue(t *testing.T, s *tfprotov5.Schema, attrs map[string]tftypes.Value) *tfprotov5.DynamicValue {
+	t.Helper()
+
+	typ := s.ValueType().(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attrType := range typ.AttributeTypes {
+		if v, ok := attrs[name]; ok {
+			values[name] = v
+		} else {
+			values[name] = tftypes.NewValue(attrType, nil)
+		}
+	}
+	v, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, values))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &v
+}
@@ -0,0 +1,202 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/vault/api"
+)
+
+// grafanaCloudPluginType is the type of the Grafana Cloud secrets plugin in
+// Vault's plugin catalog.
+const grafanaCloudPluginType = "vault-plugin-secrets-grafanacloud"
+
+// remountPollInterval is how often the status of an asynchronous remount is
+// checked.
+const remountPollInterval = time.Second
+
+// normaliseBackend trims the slashes from a mount path, which Vault ignores.
+// It is used as the StateFunc of every backend attribute.
+func normaliseBackend(v interface{}) string {
+	return strings.Trim(v.(string), "/")
+}
+
+// getMount returns the secrets engine mounted at path, or nil if there is
+// none.
+func getMount(client *api.Client, path string) (*api.MountOutput, error) {
+	mounts, err := client.Sys().ListMounts()
+	if err != nil {
+		return nil, vaultError(err, "reading", "sys/mounts", "read")
+	}
+	return mounts[strings.Trim(path, "/")+"/"], nil
+}
+
+// getMountByAccessor returns the path of the secrets engine with the given
+// accessor, and the engine, or nil if there is none. The accessor of a mount
+// is kept when it is remounted, so it finds the mount's new path.
+func getMountByAccessor(client *api.Client, accessor string) (string, *api.MountOutput, error) {
+	mounts, err := client.Sys().ListMounts()
+	if err != nil {
+		return "", nil, vaultError(err, "reading", "sys/mounts", "read")
+	}
+	for path, mount := range mounts {
+		if mount.Accessor == accessor {
+			return strings.Trim(path, "/"), mount, nil
+		}
+	}
+	return "", nil, nil
+}
+
+// getGrafanaCloudMount returns the Grafana Cloud backend mounted at path, or
+// nil if there is none. A different secrets engine mounted at path is an
+// error rather than a replacement, as replacing it would unmount something the
+// provider does not manage.
+func getGrafanaCloudMount(client *api.Client, path string) (*api.MountOutput, error) {
+	mount, err := getMount(client, path)
+	if err != nil || mount == nil {
+		return mount, err
+	}
+	if mount.Type != grafanaCloudPluginType {
+		return nil, fmt.Errorf("mount %q is a %q secrets engine, not a %q one; it may have been remounted outside of Terraform", strings.Trim(path, "/"), mount.Type, grafanaCloudPluginType)
+	}
+	return mount, nil
+}
+
+// ttlString formats a TTL in seconds for a mount input, where an empty
+// string leaves the system default in place.
+func ttlString(seconds int) string {
+	if seconds == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+// ttlStringOrSystem formats a TTL in seconds for a mount tune, where 0
+// reverts to the system default.
+func ttlStringOrSystem(seconds int) string {
+	if seconds == 0 {
+		return "system"
+	}
+	return fmt.Sprintf("%ds", seconds)
+}
+
+// parseDurationSeconds parses a TTL given as a duration string such as
+// "1h30m" or "1d", or as a number of seconds, into whole seconds, the same
+// way Vault parses TTLs.
+func parseDurationSeconds(s string) (int, error) {
+	if s == "" {
+		return 0, fmt.Errorf("must be a duration string such as \"1h30m\" or a number of seconds, got %q", s)
+	}
+	d, err := parseutil.ParseDurationSecond(s)
+	if err != nil {
+		return 0, fmt.Errorf("must be a duration string such as \"1h30m\" or a number of seconds, got %q", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("must not be negative, got %q", s)
+	}
+	if d%time.Second != 0 {
+		return 0, fmt.Errorf("must be a whole number of seconds, got %q", s)
+	}
+	return int(d / time.Second), nil
+}
+
+// validateDurationSeconds is a schema.SchemaValidateFunc for TTLs parsed by
+// parseDurationSeconds.
+func validateDurationSeconds(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %q to be string", k)}
+	}
+	if _, err := parseDurationSeconds(v); err != nil {
+		return nil, []error{fmt.Errorf("%q %s", k, err)}
+	}
+	return nil, nil
+}
+
+// normaliseDurationSeconds stores a TTL parsed by parseDurationSeconds as
+// its number of seconds, so equivalent durations do not differ.
+func normaliseDurationSeconds(v interface{}) string {
+	seconds, err := parseDurationSeconds(v.(string))
+	if err != nil {
+		return v.(string)
+	}
+	return strconv.Itoa(seconds)
+}
+
+func expandStringSlice(v interface{}) []string {
+	var result []string
+	for _, s := range v.([]interface{}) {
+		result = append(result, s.(string))
+	}
+	return result
+}
+
+func expandStringMap(v interface{}) map[string]string {
+	result := map[string]string{}
+	for k, s := range v.(map[string]interface{}) {
+		result[k] = s.(string)
+	}
+	return result
+}
+
+// tuneStringSlice expands a list for a mount tune. Vault ignores an empty
+// list, so clearing is requested with a single empty string instead.
+func tuneStringSlice(v interface{}) []string {
+	result := expandStringSlice(v)
+	if len(result) == 0 {
+		return []string{""}
+	}
+	return result
+}
+
+// remount moves the mount at from to to, keeping its roles and leases. Vault
+// 1.10 and later migrate the mount asynchronously, in which case the
+// migration is polled until it completes or timeout elapses.
+func remount(client *api.Client, from, to string, timeout time.Duration) error {
+	log.Printf("[DEBUG] Remounting %q to %q", from, to)
+	resp, err := client.Logical().Write("sys/remount", map[string]interface{}{
+		"from": from,
+		"to":   to,
+	})
+	if err != nil {
+		return vaultError(err, fmt.Sprintf("remounting %q to %q at", from, to), "sys/remount", "update and sudo")
+	}
+
+	// older versions of Vault remount synchronously and return no data
+	var migrationID string
+	if resp != nil {
+		migrationID, _ = resp.Data["migration_id"].(string)
+	}
+	if migrationID == "" {
+		log.Printf("[DEBUG] Remounted %q to %q", from, to)
+		return nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := client.Sys().RemountStatus(migrationID)
+		if err != nil && classifyVaultError(err) != vaultErrorRetryable {
+			return vaultError(err, "reading", "sys/remount/status/"+migrationID, "read and sudo")
+		}
+		if err != nil {
+			log.Printf("[DEBUG] Error reading status of remount %q, retrying: %s", migrationID, err)
+		} else if status.MigrationInfo != nil {
+			switch status.MigrationInfo.MigrationStatus {
+			case "success":
+				log.Printf("[DEBUG] Remounted %q to %q", from, to)
+				return nil
+			case "failure":
+				return fmt.Errorf("error remounting %q to %q: migration %q failed", from, to, migrationID)
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout remounting %q to %q: migration %q did not complete within %s", from, to, migrationID, timeout)
+		}
+		log.Printf("[DEBUG] Waiting for remount %q of %q to %q", migrationID, from, to)
+		time.Sleep(remountPollInterval)
+	}
+}
@@ -0,0 +1,32 @@
+package vaultgrafanacloud
+
+import "testing"
+
+func TestParseDurationSeconds(t *testing.T) {
+	tests := map[string]struct {
+		want    int
+		wantErr bool
+	}{
+		"0":     {want: 0},
+		"300":   {want: 300},
+		"90s":   {want: 90},
+		"1h30m": {want: 5400},
+		"-1":    {wantErr: true},
+		"-1h":   {wantErr: true},
+		"1.5s":  {wantErr: true},
+		"1d":    {want: 86400},
+		"-1d":   {wantErr: true},
+		"":      {wantErr: true},
+	}
+	for s, tt := range tests {
+		t.Run(s, func(t *testing.T) {
+			got, err := parseDurationSeconds(s)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
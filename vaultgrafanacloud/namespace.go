@@ -0,0 +1,102 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+// namespaceImportSeparator separates an optional namespace from the rest of
+// an import ID, e.g. "ns1/ns2:grafana-cloud".
+const namespaceImportSeparator = ":"
+
+// resourceGetter is satisfied by both schema.ResourceData and
+// schema.ResourceDiff.
+type resourceGetter interface {
+	Get(key string) interface{}
+}
+
+func namespaceSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "The Vault Enterprise namespace to manage the resource in, overriding the provider's namespace.",
+		StateFunc: func(v interface{}) string {
+			return strings.Trim(v.(string), "/")
+		},
+	}
+}
+
+func namespaceDataSourceSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The Vault Enterprise namespace to read from, overriding the provider's namespace.",
+	}
+}
+
+// getClient returns the provider's client, scoped to the resource's
+// namespace if it overrides the provider's one.
+func getClient(d resourceGetter, meta interface{}) (*api.Client, error) {
+	return getNamespaceClient(d.Get("namespace").(string), meta)
+}
+
+// getParentClient returns the provider's client holding its configured token
+// rather than its child token, scoped to the resource's namespace if it
+// overrides the provider's one. It issues the leases that must outlive the
+// Terraform run.
+func getParentClient(d resourceGetter, meta interface{}) (*api.Client, error) {
+	return namespaceClient(meta.(*providerMeta).parentClient, d.Get("namespace").(string))
+}
+
+// getNamespaceClient returns the provider's client, scoped to namespace if it
+// is set.
+func getNamespaceClient(namespace string, meta interface{}) (*api.Client, error) {
+	return namespaceClient(meta.(*providerMeta).client, namespace)
+}
+
+// namespaceClient returns client, or a clone of it scoped to namespace if it
+// is set.
+func namespaceClient(client *api.Client, namespace string) (*api.Client, error) {
+	namespace = strings.Trim(namespace, "/")
+	if namespace == "" {
+		return client, nil
+	}
+
+	client, err := client.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("error cloning Vault client: %s", err)
+	}
+	client.SetNamespace(namespace)
+	return client, nil
+}
+
+// importNamespace splits an optional "<namespace>:" prefix off the import
+// ID, storing the namespace in state and returning the remaining ID.
+func importNamespace(d *schema.ResourceData) (string, error) {
+	id := d.Id()
+	i := strings.Index(id, namespaceImportSeparator)
+	if i < 0 {
+		return id, nil
+	}
+
+	namespace := strings.Trim(id[:i], "/")
+	if err := d.Set("namespace", namespace); err != nil {
+		return "", fmt.Errorf("error setting namespace: %s", err)
+	}
+	return id[i+len(namespaceImportSeparator):], nil
+}
+
+// getRootClient returns the provider's client scoped to the root namespace,
+// for the APIs that only exist there, such as the plugin catalog.
+func getRootClient(meta interface{}) (*api.Client, error) {
+	client, err := meta.(*providerMeta).client.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("error cloning Vault client: %s", err)
+	}
+	client.ClearNamespace()
+	return client, nil
+}
@@ -0,0 +1,40 @@
+package vaultgrafanacloud
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// patchOrWrite sends the changed fields in patch to path with a JSON merge
+// PATCH. Backends that do not support PATCH, Vault servers that predate it,
+// and tokens without the patch capability, which Vault checks before routing
+// the request, are sent full with a plain write instead. Fields are sent as
+// they are, so zero values reset a field rather than being dropped.
+func patchOrWrite(client *api.Client, path string, patch, full map[string]interface{}) error {
+	if len(patch) == 0 {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Patching %q", path)
+	_, err := client.Logical().JSONMergePatch(context.Background(), path, patch)
+	if err == nil {
+		log.Printf("[DEBUG] Patched %q", path)
+		return nil
+	}
+	switch classifyVaultError(err) {
+	case vaultErrorUnsupported:
+		log.Printf("[DEBUG] %q does not support patching, writing it in full", path)
+	case vaultErrorPermissionDenied:
+		log.Printf("[DEBUG] Patching %q is not permitted, writing it in full", path)
+	default:
+		return vaultError(err, "patching", path, "patch")
+	}
+
+	if _, err := client.Logical().Write(path, full); err != nil {
+		return vaultError(err, "writing", path, "update")
+	}
+	log.Printf("[DEBUG] Wrote %q", path)
+	return nil
+}
@@ -0,0 +1,92 @@
+package vaultgrafanacloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+)
+
+func TestPatchOrWrite(t *testing.T) {
+	patch := map[string]interface{}{"ttl_seconds": float64(0)}
+	full := map[string]interface{}{"gc_role": "Viewer", "ttl_seconds": float64(0), "max_ttl_seconds": float64(300)}
+
+	tests := map[string]struct {
+		patch        map[string]interface{}
+		patchStatus  int
+		wantRequests []string
+		wantBody     map[string]interface{}
+		wantErr      bool
+	}{
+		"patched": {
+			patch:        patch,
+			patchStatus:  http.StatusNoContent,
+			wantRequests: []string{http.MethodPatch},
+			wantBody:     patch,
+		},
+		"patch unsupported": {
+			patch:        patch,
+			patchStatus:  http.StatusMethodNotAllowed,
+			wantRequests: []string{http.MethodPatch, http.MethodPut},
+			wantBody:     full,
+		},
+		"patch denied": {
+			patch:        patch,
+			patchStatus:  http.StatusForbidden,
+			wantRequests: []string{http.MethodPatch, http.MethodPut},
+			wantBody:     full,
+		},
+		"patch failed": {
+			patch:        patch,
+			patchStatus:  http.StatusInternalServerError,
+			wantRequests: []string{http.MethodPatch},
+			wantBody:     patch,
+			wantErr:      true,
+		},
+		"unchanged": {
+			patch: map[string]interface{}{},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			var body map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method)
+				body = nil
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("error decoding request: %s", err)
+				}
+				if r.Method == http.MethodPatch && tt.patchStatus != http.StatusNoContent {
+					w.WriteHeader(tt.patchStatus)
+					_, _ = w.Write([]byte(`{"errors": ["1 error occurred:\n\t* unsupported operation\n\n"]}`))
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			config := api.DefaultConfig()
+			config.Address = server.URL
+			config.MaxRetries = 0
+			client, err := api.NewClient(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = patchOrWrite(client, "grafana-cloud/roles/test", tt.patch, full)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+			if !reflect.DeepEqual(requests, tt.wantRequests) {
+				t.Errorf("expected requests %v, got %v", tt.wantRequests, requests)
+			}
+			if !reflect.DeepEqual(body, tt.wantBody) {
+				t.Errorf("expected body %v, got %v", tt.wantBody, body)
+			}
+		})
+	}
+}
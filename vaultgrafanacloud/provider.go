@@ -3,19 +3,44 @@ package vaultgrafanacloud
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/vault/api"
 	"github.com/hashicorp/vault/command/config"
 )
 
 const (
-	EnvVaultAddr  = "VAULT_ADDR"
-	EnvVaultToken = "VAULT_TOKEN"
+	EnvVaultAddr          = "VAULT_ADDR"
+	EnvVaultToken         = "VAULT_TOKEN"
+	EnvVaultNamespace     = "VAULT_NAMESPACE"
+	EnvVaultCACert        = "VAULT_CACERT"
+	EnvVaultCAPath        = "VAULT_CAPATH"
+	EnvVaultTLSServerName = "VAULT_TLS_SERVER_NAME"
+	EnvVaultSkipVerify    = "VAULT_SKIP_VERIFY"
+	EnvVaultTokenName     = "VAULT_TOKEN_NAME"
+	EnvVaultMaxTTL        = "TERRAFORM_VAULT_MAX_TTL"
+	EnvVaultSkipChild     = "TERRAFORM_VAULT_SKIP_CHILD_TOKEN"
+	EnvVaultMaxRetries    = "VAULT_MAX_RETRIES"
 
 	// DefaultMaxHTTPRetries is used for configuring the api.Client's MaxRetries.
 	DefaultMaxHTTPRetries = 2
+
+	// DefaultRequestTimeout, DefaultMinRetryWait and DefaultMaxRetryWait
+	// match the api.Client's defaults.
+	DefaultRequestTimeout = "60s"
+	DefaultMinRetryWait   = "1s"
+	DefaultMaxRetryWait   = "1.5s"
+
+	// DefaultMaxLeaseTTLSeconds is the default TTL of the child token used
+	// for a Terraform run.
+	DefaultMaxLeaseTTLSeconds = 1200
+
+	// DefaultTokenName is the default display name of the child token.
+	DefaultTokenName = "terraform-vault-grafanacloud"
 )
 
 func Provider() *schema.Provider {
@@ -29,14 +54,136 @@ func Provider() *schema.Provider {
 			},
 			"token": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc(EnvVaultToken, ""),
 				Description: "Token to use to authenticate to Vault.",
 			},
+			"ca_cert_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(EnvVaultCACert, ""),
+				Description: "Path to a file containing a PEM-encoded CA certificate used to verify the Vault server's certificate.",
+			},
+			"ca_cert_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(EnvVaultCAPath, ""),
+				Description: "Path to a directory of PEM-encoded CA certificates used to verify the Vault server's certificate.",
+			},
+			"client_auth": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "Client certificate to present to the Vault server.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"cert_file": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Path to a file containing the PEM-encoded client certificate.",
+						},
+						"key_file": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Path to a file containing the PEM-encoded private key for the client certificate.",
+						},
+					},
+				},
+			},
+			"tls_server_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(EnvVaultTLSServerName, ""),
+				Description: "Name to use as the SNI host when connecting via TLS.",
+			},
+			"skip_tls_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(EnvVaultSkipVerify, false),
+				Description: "Set this to true only if the target Vault server is an insecure development instance.",
+			},
+			"namespace": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(EnvVaultNamespace, ""),
+				Description: "The Vault Enterprise namespace to use for all operations, unless overridden by a resource.",
+			},
+			"skip_child_token": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(EnvVaultSkipChild, false),
+				Description: "Set this to true to use the configured token directly rather than a short-lived child token.",
+			},
+			"max_lease_ttl_seconds": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(EnvVaultMaxTTL, DefaultMaxLeaseTTLSeconds),
+				Description: "Maximum TTL in seconds of the child token used for the Terraform run.",
+			},
+			"token_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(EnvVaultTokenName, DefaultTokenName),
+				Description: "Display name of the child token, identifying the provider in Vault audit logs.",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc(EnvVaultMaxRetries, DefaultMaxHTTPRetries),
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of retries of a Vault request that failed with a 429 or 5xx status, or of a read that raced a preceding write.",
+			},
+			"request_timeout": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      DefaultRequestTimeout,
+				ValidateFunc: validateDuration,
+				Description:  "Timeout of a single Vault request, as a duration string such as \"30s\".",
+			},
+			"min_retry_wait": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      DefaultMinRetryWait,
+				ValidateFunc: validateDuration,
+				Description:  "Minimum time to wait before retrying a Vault request, as a duration string.",
+			},
+			"max_retry_wait": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      DefaultMaxRetryWait,
+				ValidateFunc: validateDuration,
+				Description:  "Maximum time to wait before retrying a Vault request, as a duration string.",
+			},
+			"rate_limit": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "Maximum number of Vault requests per second. Unlimited if not set.",
+			},
+			"rate_burst": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				RequiredWith: []string{"rate_limit"},
+				Description:  "Number of Vault requests allowed to exceed rate_limit in a burst. Defaults to rate_limit.",
+			},
+			authLoginApproleBlock:    authLoginApproleSchema(),
+			authLoginKubernetesBlock: authLoginKubernetesSchema(),
+			authLoginJWTBlock:        authLoginJWTSchema(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"vaultgrafanacloud_credentials":     GrafanaCloudCredentialsDataSource(),
+			"vaultgrafanacloud_secret_backend":  GrafanaCloudSecretBackendDataSource(),
+			"vaultgrafanacloud_secret_backends": GrafanaCloudSecretBackendsDataSource(),
+			"vaultgrafanacloud_secret_role":     GrafanaCloudSecretRoleDataSource(),
+			"vaultgrafanacloud_secret_roles":    GrafanaCloudSecretRolesDataSource(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"vaultgrafanacloud_secret_backend": GrafanaCloudSecretBackendResource(),
-			"vaultgrafanacloud_secret_role":    GrafanaCloudSecretRoleResource(),
+			"vaultgrafanacloud_credential":            GrafanaCloudCredentialResource(),
+			"vaultgrafanacloud_plugin":                GrafanaCloudPluginResource(),
+			"vaultgrafanacloud_secret_backend":        GrafanaCloudSecretBackendResource(),
+			"vaultgrafanacloud_secret_backend_config": GrafanaCloudSecretBackendConfigResource(),
+			"vaultgrafanacloud_secret_role":           GrafanaCloudSecretRoleResource(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
@@ -59,6 +206,16 @@ func providerToken(d *schema.ResourceData) (string, error) {
 	return strings.TrimSpace(token), nil
 }
 
+// providerMeta is the meta of a configured provider.
+type providerMeta struct {
+	// client makes every request, with the provider's child token unless
+	// skip_child_token is set.
+	client *api.Client
+	// parentClient holds the configured token, and issues the leases that
+	// must outlive the child token.
+	parentClient *api.Client
+}
+
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	clientConfig := api.DefaultConfig()
 	addr := d.Get("address").(string)
@@ -66,25 +223,68 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		clientConfig.Address = addr
 	}
 
+	tlsConfig := &api.TLSConfig{
+		CACert:        d.Get("ca_cert_file").(string),
+		CAPath:        d.Get("ca_cert_dir").(string),
+		TLSServerName: d.Get("tls_server_name").(string),
+		Insecure:      d.Get("skip_tls_verify").(bool),
+	}
+	if v, ok := d.GetOk("client_auth"); ok {
+		clientAuth := v.([]interface{})[0].(map[string]interface{})
+		tlsConfig.ClientCert = clientAuth["cert_file"].(string)
+		tlsConfig.ClientKey = clientAuth["key_file"].(string)
+	}
+	if err := clientConfig.ConfigureTLS(tlsConfig); err != nil {
+		return nil, fmt.Errorf("failed to configure TLS for Vault API: %s", err)
+	}
+
 	// enable ReadYourWrites to support read-after-write on Vault Enterprise
 	clientConfig.ReadYourWrites = true
 
-	// set default MaxRetries
-	clientConfig.MaxRetries = DefaultMaxHTTPRetries
+	clientConfig.MaxRetries = d.Get("max_retries").(int)
+	// validated by validateDuration
+	clientConfig.Timeout, _ = time.ParseDuration(d.Get("request_timeout").(string))
+	clientConfig.MinRetryWait, _ = time.ParseDuration(d.Get("min_retry_wait").(string))
+	clientConfig.MaxRetryWait, _ = time.ParseDuration(d.Get("max_retry_wait").(string))
+	if clientConfig.MinRetryWait > clientConfig.MaxRetryWait {
+		return nil, fmt.Errorf("min_retry_wait (%s) must not exceed max_retry_wait (%s)", clientConfig.MinRetryWait, clientConfig.MaxRetryWait)
+	}
 
 	client, err := api.NewClient(clientConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to configure Vault API: %s", err)
 	}
 
+	if rateLimit := d.Get("rate_limit").(float64); rateLimit > 0 {
+		burst := d.Get("rate_burst").(int)
+		if burst == 0 {
+			burst = int(math.Ceil(rateLimit))
+		}
+		client.SetLimiter(rateLimit, burst)
+	}
+
 	// setting this is critical for proper namespace handling
 	client.SetCloneHeaders(true)
 
 	// setting this is critical for proper client cloning
 	client.SetCloneToken(true)
 
-	// Try an get the token from the config or token helper
-	token, err := providerToken(d)
+	if namespace := strings.Trim(d.Get("namespace").(string), "/"); namespace != "" {
+		client.SetNamespace(namespace)
+	}
+
+	// Login if an auth method is configured, otherwise try and get the token
+	// from the config or token helper
+	var token string
+	login, err := providerAuthLogin(d)
+	if err != nil {
+		return nil, err
+	}
+	if login != nil {
+		token, err = login.login(client)
+	} else {
+		token, err = providerToken(d)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -96,5 +296,18 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		return nil, errors.New("no vault token found")
 	}
 
-	return client, nil
+	meta := &providerMeta{client: client, parentClient: client}
+	if !d.Get("skip_child_token").(bool) {
+		childToken, err := createChildToken(client, d.Get("token_name").(string), d.Get("max_lease_ttl_seconds").(int))
+		if err != nil {
+			return nil, err
+		}
+		meta.client, err = client.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("error cloning Vault client: %s", err)
+		}
+		meta.client.SetToken(childToken)
+	}
+
+	return meta, nil
 }
@@ -1,9 +1,24 @@
 package vaultgrafanacloud
 
 import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/vault/api"
 )
 
 // How to run the acceptance tests for this provider:
@@ -49,3 +64,313 @@ func init() {
 		"vaultgrafanacloud": testProvider,
 	}
 }
+
+func TestProvider_authLoginApprole(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	mount := acctest.RandomWithPrefix("tf-test-approle")
+	roleID, secretID := testAccApproleCredentials(t, mount)
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "vaultgrafanacloud" {
+	auth_login_approle {
+		mount = "%s"
+		role_id = "%s"
+		secret_id = "%s"
+	}
+}
+
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+}`, mount, roleID, secretID, backend),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "backend", backend),
+					testAccProviderTokenPolicies("tf-test-provider"),
+				),
+			},
+		},
+	})
+}
+
+// testAccClient returns a Vault client configured from the environment,
+// for setting up test fixtures outside of the provider.
+func testAccClient(t *testing.T) *api.Client {
+	t.Helper()
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("error creating Vault client: %s", err)
+	}
+	return client
+}
+
+// testProviderMeta returns the meta of a provider using client's token
+// directly, as with skip_child_token.
+func testProviderMeta(client *api.Client) *providerMeta {
+	return &providerMeta{client: client, parentClient: client}
+}
+
+// testAccProviderPolicy creates a policy granting everything the provider's
+// acceptance tests need.
+func testAccProviderPolicy(t *testing.T, client *api.Client) string {
+	t.Helper()
+	name := "tf-test-provider"
+	policy := `path "*" { capabilities = ["create", "read", "update", "delete", "list", "sudo"] }`
+	if err := client.Sys().PutPolicy(name, policy); err != nil {
+		t.Fatalf("error writing policy %q: %s", name, err)
+	}
+	return name
+}
+
+func testAccApproleCredentials(t *testing.T, mount string) (string, string) {
+	t.Helper()
+	testutil.SkipTestAcc(t)
+	testutil.TestAccPreCheck(t)
+	client := testAccClient(t)
+	policy := testAccProviderPolicy(t, client)
+
+	if err := client.Sys().EnableAuthWithOptions(mount, &api.EnableAuthOptions{Type: "approle"}); err != nil {
+		t.Fatalf("error enabling approle at %q: %s", mount, err)
+	}
+	t.Cleanup(func() {
+		if err := client.Sys().DisableAuth(mount); err != nil {
+			t.Errorf("error disabling approle at %q: %s", mount, err)
+		}
+	})
+
+	rolePath := fmt.Sprintf("auth/%s/role/test", mount)
+	if _, err := client.Logical().Write(rolePath, map[string]interface{}{
+		"token_policies": []string{policy},
+	}); err != nil {
+		t.Fatalf("error writing %q: %s", rolePath, err)
+	}
+	resp, err := client.Logical().Read(rolePath + "/role-id")
+	if err != nil {
+		t.Fatalf("error reading role ID: %s", err)
+	}
+	roleID := resp.Data["role_id"].(string)
+	resp, err = client.Logical().Write(rolePath+"/secret-id", nil)
+	if err != nil {
+		t.Fatalf("error generating secret ID: %s", err)
+	}
+	return roleID, resp.Data["secret_id"].(string)
+}
+
+// testAccProviderTokenPolicies checks that the provider authenticated with a
+// token carrying the given policy rather than the root token.
+func testAccProviderTokenPolicies(policy string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testProvider.Meta().(*providerMeta).client
+		resp, err := client.Auth().Token().LookupSelf()
+		if err != nil {
+			return err
+		}
+		policies, err := resp.TokenPolicies()
+		if err != nil {
+			return err
+		}
+		for _, p := range policies {
+			if p == policy {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected provider token to have policy %q, got %v", policy, policies)
+	}
+}
+
+func TestProvider_authLoginJWT(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	mount := acctest.RandomWithPrefix("tf-test-jwt")
+	jwt := testAccJWTCredentials(t, mount)
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "vaultgrafanacloud" {
+	auth_login_jwt {
+		mount = "%s"
+		role = "test"
+		jwt = "%s"
+	}
+}
+
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+}`, mount, jwt, backend),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "backend", backend),
+					testAccProviderTokenPolicies("tf-test-provider"),
+				),
+			},
+		},
+	})
+}
+
+// testAccJWTCredentials configures a JWT auth method trusting a freshly
+// generated key, and returns a JWT signed with it.
+func testAccJWTCredentials(t *testing.T, mount string) string {
+	t.Helper()
+	testutil.SkipTestAcc(t)
+	testutil.TestAccPreCheck(t)
+	client := testAccClient(t)
+	policy := testAccProviderPolicy(t, client)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error generating key: %s", err)
+	}
+	pubKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("error marshalling public key: %s", err)
+	}
+
+	if err := client.Sys().EnableAuthWithOptions(mount, &api.EnableAuthOptions{Type: "jwt"}); err != nil {
+		t.Fatalf("error enabling jwt at %q: %s", mount, err)
+	}
+	t.Cleanup(func() {
+		if err := client.Sys().DisableAuth(mount); err != nil {
+			t.Errorf("error disabling jwt at %q: %s", mount, err)
+		}
+	})
+
+	configPath := fmt.Sprintf("auth/%s/config", mount)
+	if _, err := client.Logical().Write(configPath, map[string]interface{}{
+		"jwt_validation_pubkeys": []string{
+			string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubKey})),
+		},
+	}); err != nil {
+		t.Fatalf("error writing %q: %s", configPath, err)
+	}
+	rolePath := fmt.Sprintf("auth/%s/role/test", mount)
+	if _, err := client.Logical().Write(rolePath, map[string]interface{}{
+		"role_type":       "jwt",
+		"user_claim":      "sub",
+		"bound_audiences": []string{"tf-test"},
+		"token_policies":  []string{policy},
+	}); err != nil {
+		t.Fatalf("error writing %q: %s", rolePath, err)
+	}
+
+	now := time.Now()
+	header, _ := json.Marshal(map[string]interface{}{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"sub": "tf-test",
+		"aud": "tf-test",
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	})
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("error signing JWT: %s", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestProvider_invalidClientConfig(t *testing.T) {
+	tests := map[string]map[string]interface{}{
+		"missing CA cert": {
+			"ca_cert_file": "does-not-exist.pem",
+		},
+		"min retry wait above max": {
+			"min_retry_wait": "2s",
+			"max_retry_wait": "1s",
+		},
+		"client cert without key": {
+			"client_auth": []interface{}{
+				map[string]interface{}{
+					"cert_file": "does-not-exist.pem",
+					"key_file":  "",
+				},
+			},
+		},
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			raw["address"] = "https://127.0.0.1:8200"
+			raw["token"] = "token"
+			d := schema.TestResourceDataRaw(t, Provider().Schema, raw)
+			if _, err := providerConfigure(d); err == nil {
+				t.Fatal("expected an error configuring the client")
+			}
+		})
+	}
+}
+
+func TestProvider_childToken(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	tokenName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "vaultgrafanacloud" {
+	token_name = "%s"
+	max_lease_ttl_seconds = 600
+}
+
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+}`, tokenName, backend),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "backend", backend),
+					testAccProviderChildToken(tokenName, 600),
+				),
+			},
+		},
+	})
+}
+
+// testAccProviderChildToken checks that the provider is using a child token
+// with the given name and a TTL it expires at, then revokes it. Only this
+// provider's child token is revoked; the next configuration issues another.
+func testAccProviderChildToken(tokenName string, maxTTL int) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testProvider.Meta().(*providerMeta).client
+		resp, err := client.Auth().Token().LookupSelf()
+		if err != nil {
+			return err
+		}
+		if displayName := resp.Data["display_name"]; displayName != "token-"+tokenName {
+			return fmt.Errorf("expected child token display name %q, got %q", "token-"+tokenName, displayName)
+		}
+		ttl, err := resp.TokenTTL()
+		if err != nil {
+			return err
+		}
+		if ttl <= 0 || ttl > time.Duration(maxTTL)*time.Second {
+			return fmt.Errorf("expected child token TTL within %ds, got %s", maxTTL, ttl)
+		}
+
+		if err := client.Auth().Token().RevokeSelf(""); err != nil {
+			return fmt.Errorf("error revoking child token: %s", err)
+		}
+		if _, err := client.Auth().Token().LookupSelf(); err == nil {
+			return fmt.Errorf("expected child token to be revoked")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,238 @@
+package vaultgrafanacloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/vault/api"
+)
+
+func GrafanaCloudCredentialResource() *schema.Resource {
+	return &schema.Resource{
+		Create:        grafanaCloudCredentialCreate,
+		Delete:        grafanaCloudCredentialDelete,
+		Read:          grafanaCloudCredentialRead,
+		Update:        grafanaCloudCredentialUpdate,
+		CustomizeDiff: grafanaCloudCredentialCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"backend": {
+				Type:        schema.TypeString,
+				Default:     "grafana-cloud",
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The mount path of the Grafana Cloud backend.",
+				StateFunc:   normaliseBackend,
+			},
+			"namespace": namespaceSchema(),
+			"role": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the role to issue credentials for",
+			},
+			"renew_before_seconds": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Replace the credentials once the remaining lease TTL drops below this many seconds",
+			},
+			"key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The issued Grafana Cloud API key",
+			},
+			"user": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The User configured on the backend, needed to interact with prometheus",
+			},
+			"lease_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The lease ID of the issued credentials",
+			},
+			"lease_duration": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The remaining lease duration of the issued credentials in seconds, as of the last refresh",
+			},
+			"lease_renewable": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the lease of the issued credentials is renewable",
+			},
+			"lease_expire_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time the lease of the issued credentials expires, in RFC3339 format",
+			},
+		},
+	}
+}
+
+func grafanaCloudCredentialCreate(d *schema.ResourceData, meta interface{}) error {
+	// Vault revokes the leases of a token along with it, so the credentials
+	// are issued with the provider's configured token rather than its
+	// short-lived child token.
+	client, err := getParentClient(d, meta)
+	if err != nil {
+		return err
+	}
+	backend := normaliseBackend(d.Get("backend"))
+	credsPath := fmt.Sprintf("%s/creds/%s", backend, d.Get("role").(string))
+
+	log.Printf("[DEBUG] Issuing credentials from %q", credsPath)
+	resp, err := client.Logical().Read(credsPath)
+	if err != nil {
+		return vaultError(err, "reading", credsPath, "read")
+	}
+	log.Printf("[DEBUG] Issued credentials from %q", credsPath)
+	if resp == nil {
+		return fmt.Errorf("no credentials returned by %q", credsPath)
+	}
+	if resp.LeaseID == "" {
+		return fmt.Errorf("no lease returned by %q", credsPath)
+	}
+
+	d.SetId(resp.LeaseID)
+	if err := setGrafanaCloudCredentials(d, resp); err != nil {
+		return err
+	}
+	return setGrafanaCloudCredentialExpiry(d, resp.LeaseDuration)
+}
+
+func grafanaCloudCredentialRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getParentClient(d, meta)
+	if err != nil {
+		return err
+	}
+	leaseID := d.Id()
+
+	log.Printf("[DEBUG] Looking up lease %q", leaseID)
+	resp, err := client.Sys().Lookup(leaseID)
+	if err != nil && isNotFound(err) {
+		log.Printf("[WARN] Lease %q not found, removing credentials from state", leaseID)
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return vaultError(err, fmt.Sprintf("looking up lease %q at", leaseID), "sys/leases/lookup", "update")
+	}
+	log.Printf("[DEBUG] Looked up lease %q", leaseID)
+	if resp == nil {
+		log.Printf("[WARN] Lease %q not found, removing credentials from state", leaseID)
+		d.SetId("")
+		return nil
+	}
+
+	renewable, _ := resp.Data["renewable"].(bool)
+	ttl, err := leaseTTL(resp)
+	if err != nil {
+		return fmt.Errorf("error reading TTL of lease %q: %s", leaseID, err)
+	}
+
+	if renewable {
+		log.Printf("[DEBUG] Renewing lease %q", leaseID)
+		renewed, err := client.Sys().Renew(leaseID, 0)
+		if err != nil && isNotFound(err) {
+			log.Printf("[WARN] Lease %q not found, removing credentials from state", leaseID)
+			d.SetId("")
+			return nil
+		}
+		if err != nil {
+			return vaultError(err, fmt.Sprintf("renewing lease %q at", leaseID), "sys/leases/renew", "update")
+		}
+		log.Printf("[DEBUG] Renewed lease %q", leaseID)
+		if renewed != nil {
+			ttl = renewed.LeaseDuration
+			renewable = renewed.Renewable
+		}
+	}
+
+	if err := d.Set("lease_id", leaseID); err != nil {
+		return fmt.Errorf("error setting lease_id: %s", err)
+	}
+	if err := d.Set("lease_renewable", renewable); err != nil {
+		return fmt.Errorf("error setting lease_renewable: %s", err)
+	}
+	return setGrafanaCloudCredentialExpiry(d, ttl)
+}
+
+func grafanaCloudCredentialUpdate(d *schema.ResourceData, meta interface{}) error {
+	// Only renew_before_seconds can change in place, and it is not sent to
+	// Vault.
+	return grafanaCloudCredentialRead(d, meta)
+}
+
+func grafanaCloudCredentialDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getParentClient(d, meta)
+	if err != nil {
+		return err
+	}
+	leaseID := d.Id()
+
+	log.Printf("[DEBUG] Revoking lease %q", leaseID)
+	if err := client.Sys().Revoke(leaseID); err != nil && !isNotFound(err) {
+		return vaultError(err, fmt.Sprintf("revoking lease %q at", leaseID), "sys/leases/revoke", "update")
+	}
+	log.Printf("[DEBUG] Revoked lease %q", leaseID)
+	return nil
+}
+
+// grafanaCloudCredentialCustomizeDiff replaces the credentials once the
+// remaining lease TTL drops below renew_before_seconds.
+func grafanaCloudCredentialCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	expireTime, err := time.Parse(time.RFC3339, d.Get("lease_expire_time").(string))
+	if err != nil {
+		// Without a known expiry, leave the credentials alone.
+		return nil
+	}
+	renewBefore := time.Duration(d.Get("renew_before_seconds").(int)) * time.Second
+	if time.Until(expireTime) >= renewBefore {
+		return nil
+	}
+
+	log.Printf("[DEBUG] Lease %q expires at %s, replacing credentials", d.Id(), expireTime.Format(time.RFC3339))
+	for _, k := range []string{"key", "lease_id"} {
+		if err := d.SetNewComputed(k); err != nil {
+			return err
+		}
+	}
+	return d.ForceNew("lease_id")
+}
+
+func setGrafanaCloudCredentialExpiry(d *schema.ResourceData, ttl int) error {
+	if err := d.Set("lease_duration", ttl); err != nil {
+		return fmt.Errorf("error setting lease_duration: %s", err)
+	}
+	expireTime := time.Now().Add(time.Duration(ttl) * time.Second).UTC().Format(time.RFC3339)
+	if err := d.Set("lease_expire_time", expireTime); err != nil {
+		return fmt.Errorf("error setting lease_expire_time: %s", err)
+	}
+	return nil
+}
+
+// leaseTTL returns the remaining TTL in seconds from a response of
+// sys/leases/lookup.
+func leaseTTL(resp *api.Secret) (int, error) {
+	switch v := resp.Data["ttl"].(type) {
+	case json.Number:
+		ttl, err := v.Int64()
+		return int(ttl), err
+	case nil:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T", v)
+	}
+}
@@ -0,0 +1,182 @@
+package vaultgrafanacloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/vault/api"
+)
+
+func TestGrafanaCloudCredential(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	testutil.SkipTestAcc(t)
+	grafanaCloud := testutil.TestCredsPreCheck(t)
+	var leaseID string
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testProviders,
+		PreCheck:     func() { testutil.TestAccPreCheck(t) },
+		CheckDestroy: testAccGrafanaCloudCredentialCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudCredential_config(backend, grafanaCloud, 0),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_credential.test", "backend", backend),
+					resource.TestCheckResourceAttrSet("vaultgrafanacloud_credential.test", "key"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_credential.test", "user", grafanaCloud[3]),
+					resource.TestCheckResourceAttrSet("vaultgrafanacloud_credential.test", "lease_expire_time"),
+					testAccGrafanaCloudCredentialLeaseID(&leaseID),
+					testAccGrafanaCloudCredentialsOutliveRun("vaultgrafanacloud_credential.test"),
+				),
+			},
+			{
+				// The role's TTL is below the threshold, so the credentials
+				// are replaced on every apply.
+				Config: testGrafanaCloudCredential_config(backend, grafanaCloud, 3600),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_credential.test", "renew_before_seconds", "3600"),
+					testAccGrafanaCloudCredentialLeaseReplaced(&leaseID),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+// TestGrafanaCloudCredential_parentToken checks the credentials are issued,
+// renewed and revoked with the provider's configured token, so their lease
+// outlives the provider's child token.
+func TestGrafanaCloudCredential_parentToken(t *testing.T) {
+	leaseID := "grafana-cloud/creds/test/abc"
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token := r.Header.Get("X-Vault-Token"); token != "parent" {
+			t.Errorf("expected %s %s to use the parent token, got %q", r.Method, r.URL.Path, token)
+		}
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/v1/grafana-cloud/creds/test":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"lease_id":       leaseID,
+				"lease_duration": 300,
+				"renewable":      true,
+				"data":           map[string]interface{}{"key": "key", "user": "user"},
+			})
+		case "/v1/sys/leases/lookup":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"id": leaseID, "ttl": 200, "renewable": true},
+			})
+		case "/v1/sys/leases/renew":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"lease_id":       leaseID,
+				"lease_duration": 300,
+				"renewable":      true,
+			})
+		case "/v1/sys/leases/revoke":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	config.MaxRetries = 0
+	parent, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent.SetToken("parent")
+	child, err := parent.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	child.SetToken("child")
+	meta := &providerMeta{client: child, parentClient: parent}
+
+	r := GrafanaCloudCredentialResource()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"role": "test",
+	})
+	d.MarkNewResource()
+	if err := r.Create(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != leaseID {
+		t.Errorf("expected ID %q, got %q", leaseID, d.Id())
+	}
+	if err := r.Read(d, meta); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Delete(d, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"/v1/grafana-cloud/creds/test", "/v1/sys/leases/lookup", "/v1/sys/leases/renew", "/v1/sys/leases/revoke"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected requests to %v, got %v", expected, paths)
+	}
+}
+
+func testAccGrafanaCloudCredentialLeaseID(leaseID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["vaultgrafanacloud_credential.test"]
+		if !ok {
+			return fmt.Errorf("resource not found in state")
+		}
+		*leaseID = rs.Primary.ID
+		return nil
+	}
+}
+
+func testAccGrafanaCloudCredentialLeaseReplaced(leaseID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["vaultgrafanacloud_credential.test"]
+		if !ok {
+			return fmt.Errorf("resource not found in state")
+		}
+		if rs.Primary.ID == *leaseID {
+			return fmt.Errorf("expected lease %q to be replaced", *leaseID)
+		}
+
+		client := testProvider.Meta().(*providerMeta).client
+		if _, err := client.Sys().Lookup(*leaseID); err == nil {
+			return fmt.Errorf("expected replaced lease %q to be revoked", *leaseID)
+		}
+		*leaseID = rs.Primary.ID
+		return nil
+	}
+}
+
+func testAccGrafanaCloudCredentialCheckDestroy(s *terraform.State) error {
+	client := testProvider.Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vaultgrafanacloud_credential" {
+			continue
+		}
+		if _, err := client.Sys().Lookup(rs.Primary.ID); err == nil {
+			return fmt.Errorf("lease %q still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testGrafanaCloudCredential_config(backend string, grafanaCloud []string, renewBefore int) string {
+	return testGrafanaCloudCredentialsConfig(backend, grafanaCloud) + fmt.Sprintf(`
+resource "vaultgrafanacloud_credential" "test" {
+	backend = vaultgrafanacloud_secret_role.test.backend
+	role = vaultgrafanacloud_secret_role.test.name
+	renew_before_seconds = %d
+}`, renewBefore)
+}
@@ -0,0 +1,226 @@
+package vaultgrafanacloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/vault/api"
+)
+
+// pluginImportVersionSeparator separates the name and version of a versioned
+// plugin in an import ID, e.g. "vault-plugin-secrets-grafanacloud@v1.0.0".
+const pluginImportVersionSeparator = "@"
+
+func GrafanaCloudPluginResource() *schema.Resource {
+	return &schema.Resource{
+		Create: grafanaCloudPluginCreate,
+		Delete: grafanaCloudPluginDelete,
+		Read:   grafanaCloudPluginRead,
+		Update: grafanaCloudPluginUpdate,
+		Importer: &schema.ResourceImporter{
+			StateContext: grafanaCloudPluginImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Default:     grafanaCloudPluginType,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The name of the plugin in the catalog, used as the type when mounting a backend",
+			},
+			"sha256": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The SHA256 sum of the plugin binary",
+			},
+			"command": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The command used to execute the plugin, relative to Vault's plugin directory. Defaults to the name",
+			},
+			"args": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arguments passed to the plugin command",
+			},
+			"env": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Sensitive:   true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Environment variables set for the plugin command",
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The semantic version of the plugin, e.g. \"v1.0.0\". Reference it from a backend's plugin_version to pin the mount to it. Changing it registers the new version, reloads the backends using the plugin and deregisters the old version",
+			},
+		},
+	}
+}
+
+func grafanaCloudPluginCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getRootClient(meta)
+	if err != nil {
+		return err
+	}
+	name := d.Get("name").(string)
+
+	if err := grafanaCloudPluginRegister(client, d); err != nil {
+		return err
+	}
+	d.SetId(name)
+	return grafanaCloudPluginRead(d, meta)
+}
+
+func grafanaCloudPluginDelete(d *schema.ResourceData, meta interface{}) error {
+	client, err := getRootClient(meta)
+	if err != nil {
+		return err
+	}
+	return grafanaCloudPluginDeregister(client, d.Id(), d.Get("version").(string))
+}
+
+func grafanaCloudPluginRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getRootClient(meta)
+	if err != nil {
+		return err
+	}
+	pluginPath := grafanaCloudPluginPath(d.Id())
+	log.Printf("[DEBUG] Reading %q", pluginPath)
+
+	if err := d.Set("name", d.Id()); err != nil {
+		return fmt.Errorf("error setting name: %s", err)
+	}
+
+	resp, err := client.Logical().ReadWithData(pluginPath, grafanaCloudPluginVersionData(d.Get("version").(string)))
+	if err != nil {
+		return vaultError(err, "reading", pluginPath, "read and sudo")
+	}
+	log.Printf("[DEBUG] Read %q", pluginPath)
+	if resp == nil {
+		log.Printf("[WARN] %q not found, removing from state", pluginPath)
+		d.SetId("")
+		return nil
+	}
+
+	// the environment is never returned by Vault, so is left as configured
+	for _, k := range []string{"sha256", "command", "args", "version"} {
+		if val, ok := resp.Data[k]; ok {
+			if err := d.Set(k, val); err != nil {
+				return fmt.Errorf("error setting state key '%s': %s", k, err)
+			}
+		}
+	}
+	return nil
+}
+
+func grafanaCloudPluginUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getRootClient(meta)
+	if err != nil {
+		return err
+	}
+	name := d.Id()
+
+	// a new version is registered alongside the old one, which is only
+	// deregistered once the backends have been reloaded
+	if err := grafanaCloudPluginRegister(client, d); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Reloading backends of plugin %q", name)
+	if _, err := client.Sys().ReloadPlugin(&api.ReloadPluginInput{Plugin: name}); err != nil {
+		return vaultError(err, "writing", "sys/plugins/reload/backend", "update and sudo")
+	}
+	log.Printf("[DEBUG] Reloaded backends of plugin %q", name)
+
+	if d.HasChange("version") {
+		oldVersion, _ := d.GetChange("version")
+		if err := grafanaCloudPluginDeregister(client, name, oldVersion.(string)); err != nil {
+			return err
+		}
+	}
+	return grafanaCloudPluginRead(d, meta)
+}
+
+// grafanaCloudPluginImport imports a plugin by its name, or by
+// "<name>@<version>" for a versioned plugin.
+func grafanaCloudPluginImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	name := d.Id()
+	if i := strings.LastIndex(name, pluginImportVersionSeparator); i >= 0 {
+		if err := d.Set("version", name[i+len(pluginImportVersionSeparator):]); err != nil {
+			return nil, fmt.Errorf("error setting version: %s", err)
+		}
+		name = name[:i]
+	}
+	if name == "" {
+		return nil, fmt.Errorf("invalid plugin ID %q: expected the plugin name", d.Id())
+	}
+	d.SetId(name)
+	return []*schema.ResourceData{d}, nil
+}
+
+func grafanaCloudPluginRegister(client *api.Client, d *schema.ResourceData) error {
+	name := d.Get("name").(string)
+	pluginPath := grafanaCloudPluginPath(name)
+
+	command := d.Get("command").(string)
+	if command == "" {
+		command = name
+	}
+	var env []string
+	for k, v := range d.Get("env").(map[string]interface{}) {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(env)
+
+	data := map[string]interface{}{
+		"sha256":  d.Get("sha256").(string),
+		"command": command,
+		"args":    expandStringSlice(d.Get("args")),
+		"env":     env,
+	}
+	if v, ok := d.GetOk("version"); ok {
+		data["version"] = v
+	}
+
+	log.Printf("[DEBUG] Registering %q", pluginPath)
+	if _, err := client.Logical().Write(pluginPath, data); err != nil {
+		return vaultError(err, "registering", pluginPath, "update and sudo")
+	}
+	log.Printf("[DEBUG] Registered %q", pluginPath)
+	return nil
+}
+
+// grafanaCloudPluginDeregister deregisters the given version of the plugin,
+// or the unversioned plugin if version is empty.
+func grafanaCloudPluginDeregister(client *api.Client, name, version string) error {
+	pluginPath := grafanaCloudPluginPath(name)
+	log.Printf("[DEBUG] Deregistering %q", pluginPath)
+
+	if _, err := client.Logical().DeleteWithData(pluginPath, grafanaCloudPluginVersionData(version)); err != nil && !isNotFound(err) {
+		return vaultError(err, "deregistering", pluginPath, "delete and sudo")
+	}
+	log.Printf("[DEBUG] Deregistered %q", pluginPath)
+	return nil
+}
+
+func grafanaCloudPluginPath(name string) string {
+	return fmt.Sprintf("sys/plugins/catalog/secret/%s", name)
+}
+
+// grafanaCloudPluginVersionData selects a version of the plugin when reading
+// or deregistering it.
+func grafanaCloudPluginVersionData(version string) map[string][]string {
+	if version != "" {
+		return map[string][]string{"version": {version}}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package vaultgrafanacloud
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/vault/api"
+)
+
+// testPluginBinary is the plugin binary mounted into Vault's plugin directory
+// by docker-compose.yaml.
+const testPluginBinary = "../bin/vault-plugin-secrets-grafanacloud"
+
+func TestGrafanaCloudPlugin(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-test-grafanacloud-plugin")
+	sum := testAccPluginSHA256(t)
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testProviders,
+		PreCheck:     func() { testutil.TestAccPreCheck(t) },
+		CheckDestroy: testAccGrafanaCloudPluginCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudPlugin_config(name, sum, `[]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_plugin.test", "name", name),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_plugin.test", "sha256", sum),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_plugin.test", "command", grafanaCloudPluginType),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_plugin.test", "args.#", "0"),
+				),
+			},
+			{
+				Config: testGrafanaCloudPlugin_config(name, sum, `["-tls-skip-verify"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_plugin.test", "args.#", "1"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_plugin.test", "args.0", "-tls-skip-verify"),
+				),
+			},
+			{
+				ResourceName:            "vaultgrafanacloud_plugin.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"env"},
+			},
+		},
+	})
+}
+
+func TestGrafanaCloudPlugin_version(t *testing.T) {
+	name := acctest.RandomWithPrefix("tf-test-grafanacloud-plugin")
+	sum := testAccPluginSHA256(t)
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testProviders,
+		PreCheck:     func() { testutil.TestAccPreCheck(t) },
+		CheckDestroy: testAccGrafanaCloudPluginCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudPlugin_versionConfig(name, sum, "v1.0.0"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_plugin.test", "version", "v1.0.0"),
+					testAccGrafanaCloudPluginVersionRegistered(name, "v1.0.0", true),
+				),
+			},
+			{
+				// the old version is deregistered rather than left behind
+				Config: testGrafanaCloudPlugin_versionConfig(name, sum, "v1.0.1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_plugin.test", "version", "v1.0.1"),
+					testAccGrafanaCloudPluginVersionRegistered(name, "v1.0.1", true),
+					testAccGrafanaCloudPluginVersionRegistered(name, "v1.0.0", false),
+				),
+			},
+		},
+	})
+}
+
+// TestGrafanaCloudPluginUpdate_version checks a new version is registered and
+// the backends reloaded before the old version is deregistered, in place.
+func TestGrafanaCloudPluginUpdate_version(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, r.URL.Query().Get("version")))
+		switch {
+		case r.URL.Path == "/v1/sys/plugins/catalog/secret/test" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{
+					"sha256":  "abc",
+					"command": "test",
+					"args":    []string{},
+					"version": r.URL.Query().Get("version"),
+				},
+			})
+		case r.URL.Path == "/v1/sys/plugins/reload/backend":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{}})
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	config.MaxRetries = 0
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := testProviderMeta(client)
+
+	r := GrafanaCloudPluginResource()
+	state := &terraform.InstanceState{
+		ID: "test",
+		Attributes: map[string]string{
+			"id":      "test",
+			"name":    "test",
+			"sha256":  "abc",
+			"command": "test",
+			"args.#":  "0",
+			"version": "v1.0.0",
+		},
+	}
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":    "test",
+		"sha256":  "abc",
+		"version": "v1.0.1",
+	}), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.RequiresNew() {
+		t.Fatal("expected a new version to update the plugin in place")
+	}
+	newState, diags := r.Apply(context.Background(), state, diff, meta)
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+	if v := newState.Attributes["version"]; v != "v1.0.1" {
+		t.Errorf("expected version %q, got %q", "v1.0.1", v)
+	}
+
+	expected := []string{
+		"PUT /v1/sys/plugins/catalog/secret/test ",
+		"PUT /v1/sys/plugins/reload/backend ",
+		"DELETE /v1/sys/plugins/catalog/secret/test v1.0.0",
+		"GET /v1/sys/plugins/catalog/secret/test v1.0.1",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %q, got %q", expected, requests)
+	}
+}
+
+func testAccGrafanaCloudPluginVersionRegistered(name, version string, registered bool) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testProvider.Meta().(*providerMeta).client
+		resp, err := client.Logical().ReadWithData(grafanaCloudPluginPath(name), map[string][]string{"version": {version}})
+		if err != nil {
+			return err
+		}
+		if (resp != nil) != registered {
+			return fmt.Errorf("expected plugin %q version %q to be registered: %t", name, version, registered)
+		}
+		return nil
+	}
+}
+
+func testGrafanaCloudPlugin_versionConfig(name, sum, version string) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_plugin" "test" {
+	name = "%s"
+	sha256 = "%s"
+	command = "%s"
+	version = "%s"
+}`, name, sum, grafanaCloudPluginType, version)
+}
+
+func testAccPluginSHA256(t *testing.T) string {
+	t.Helper()
+	testutil.SkipTestAcc(t)
+	b, err := os.ReadFile(testPluginBinary)
+	if err != nil {
+		t.Fatalf("error reading plugin binary: %s", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func testAccGrafanaCloudPluginCheckDestroy(s *terraform.State) error {
+	client := testProvider.Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "vaultgrafanacloud_plugin" {
+			continue
+		}
+		resp, err := client.Logical().Read(grafanaCloudPluginPath(rs.Primary.ID))
+		if err != nil {
+			return err
+		}
+		if resp != nil {
+			return fmt.Errorf("Plugin %q still registered", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testGrafanaCloudPlugin_config(name, sum, args string) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_plugin" "test" {
+	name = "%s"
+	sha256 = "%s"
+	command = "%s"
+	args = %s
+	env = {
+		TEST = "true"
+	}
+}`, name, sum, grafanaCloudPluginType, args)
+}
@@ -1,126 +1,315 @@
 package vaultgrafanacloud
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/vault/api"
 )
 
 func GrafanaCloudSecretBackendResource() *schema.Resource {
 	return &schema.Resource{
-		SchemaVersion: 1,
+		SchemaVersion: 2,
 		Create:        grafanaCloudSecretBackendCreate,
 		Delete:        grafanaCloudSecretBackendDelete,
 		Read:          grafanaCloudSecretBackendRead,
 		Update:        grafanaCloudSecretBackendUpdate,
-
-		Schema: map[string]*schema.Schema{
-			"backend": {
-				Type:        schema.TypeString,
-				Default:     "grafana-cloud",
-				ForceNew:    true,
-				Optional:    true,
-				Description: `The mount path for a backend, for example, the path given in "$ vault secrets enable -path=grafana-cloud grafana-cloud-plugin".`,
-				StateFunc: func(v interface{}) string {
-					return strings.Trim(v.(string), "/")
-				},
-			},
-			"key": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Sensitive:   true,
-				Description: "API key with Admin role to create user keys",
-			},
-			"url": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The URL for the Grafana Cloud API",
-			},
-			"organisation": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The Organisation slug for the Grafana Cloud API",
-			},
-			"user": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The User that is needed to interact with prometheus, if set this is returned alongside every issued credential",
+		Importer: &schema.ResourceImporter{
+			StateContext: grafanaCloudSecretBackendImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 1,
+				Type:    grafanaCloudSecretBackendStateTypeV1(),
+				Upgrade: grafanaCloudSecretBackendRemoveKey,
 			},
 		},
+
+		Schema: grafanaCloudSecretBackendSchema(),
 	}
 }
 
-func grafanaCloudSecretBackendCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
-	backend := d.Get("backend").(string)
-
-	log.Printf("[DEBUG] Mounting grafana-cloud-plugin backend at %q", backend)
-	err := client.Sys().Mount(backend, &api.MountInput{
-		Type: "vault-plugin-secrets-grafanacloud",
-	})
-	if err != nil {
-		return fmt.Errorf("error mounting to %q: %s", backend, err)
+func grafanaCloudSecretBackendSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"backend": {
+			Type:        schema.TypeString,
+			Default:     "grafana-cloud",
+			Optional:    true,
+			Description: `The mount path for a backend, for example, the path given in "$ vault secrets enable -path=grafana-cloud grafana-cloud-plugin". Changing this remounts the backend in place.`,
+			StateFunc:   normaliseBackend,
+		},
+		"namespace": namespaceSchema(),
+		"key": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			ExactlyOneOf:     []string{"key", "key_wo"},
+			Deprecated:       "Use key_wo, which is never stored in the Terraform state, instead",
+			DiffSuppressFunc: suppressUnreadKey,
+			Description:      "API key with Admin role to create user keys",
+		},
+		"key_wo": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			WriteOnly:   true,
+			Description: "API key with Admin role to create user keys, which is never stored in the Terraform state. Requires Terraform 1.11 or later",
+		},
+		"key_version": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "Version of the key. Changing it writes key_wo or key to the backend again",
+		},
+		"url": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The URL for the Grafana Cloud API",
+		},
+		"organisation": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The Organisation slug for the Grafana Cloud API",
+		},
+		"user": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The User that is needed to interact with prometheus, if set this is returned alongside every issued credential",
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Human-friendly description of the mount",
+		},
+		"default_lease_ttl_seconds": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "Default lease duration for credentials in seconds, 0 uses the system default",
+		},
+		"max_lease_ttl_seconds": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "Maximum possible lease duration for credentials in seconds, 0 uses the system default",
+		},
+		"local": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "Whether the mount is local only, i.e. not replicated",
+		},
+		"seal_wrap": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			ForceNew:    true,
+			Description: "Whether to enable seal wrapping for the mount",
+		},
+		"audit_non_hmac_request_keys": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Keys that will not be HMAC'd by audit devices in the request data object",
+		},
+		"audit_non_hmac_response_keys": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Keys that will not be HMAC'd by audit devices in the response data object",
+		},
+		"listing_visibility": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice([]string{"", "unauth", "hidden"}, false),
+			Description:  `Whether to show the mount in the UI-specific listing endpoint, either "unauth" or "hidden"`,
+		},
+		"plugin_version": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The semantic version of the plugin to use, e.g. \"v1.0.0\". Uses the unversioned plugin if not set",
+		},
+		"options": {
+			Type:        schema.TypeMap,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Mount options passed to the plugin",
+		},
+		"adopt_existing": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Adopt a Grafana Cloud backend already mounted at the path on create, instead of failing",
+		},
 	}
+}
 
-	log.Printf("[DEBUG] Mounted vault grafana cloud backend at %q", backend)
-	d.SetId(backend)
+// grafanaCloudSecretBackendTuneFields are the fields of the mount that can be
+// updated in place with sys/mounts/<path>/tune.
+var grafanaCloudSecretBackendTuneFields = []string{
+	"description",
+	"default_lease_ttl_seconds",
+	"max_lease_ttl_seconds",
+	"audit_non_hmac_request_keys",
+	"audit_non_hmac_response_keys",
+	"listing_visibility",
+	"plugin_version",
+	"options",
+}
 
-	data := map[string]interface{}{}
-	if v, ok := d.GetOkExists("key"); ok {
-		data["key"] = v
-	}
-	if v, ok := d.GetOkExists("url"); ok {
-		data["url"] = v
+func grafanaCloudSecretBackendCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
 	}
-	if v, ok := d.GetOkExists("organisation"); ok {
-		data["organisation"] = v
+	backend := d.Get("backend").(string)
+
+	var existing *api.MountOutput
+	if d.Get("adopt_existing").(bool) {
+		log.Printf("[DEBUG] Reading mount %q", backend)
+		existing, err = getGrafanaCloudMount(client, backend)
+		if err != nil {
+			return err
+		}
+		log.Printf("[DEBUG] Read mount %q", backend)
+		if existing != nil {
+			if err := checkAdoptedGrafanaCloudMount(d, existing); err != nil {
+				return err
+			}
+		}
 	}
-	if v, ok := d.GetOkExists("user"); ok {
-		data["user"] = v
+
+	if existing == nil {
+		if err := grafanaCloudSecretBackendMount(client, d); err != nil {
+			return err
+		}
+		d.SetId(backend)
+	} else {
+		// An adopted mount is tuned to the mount fields that are configured,
+		// as they differ from the empty prior state.
+		log.Printf("[DEBUG] Adopting vault grafana cloud backend at %q", backend)
+		d.SetId(backend)
+		if d.HasChanges(grafanaCloudSecretBackendTuneFields...) {
+			if err := grafanaCloudSecretBackendTune(client, d); err != nil {
+				return err
+			}
+		}
 	}
 
 	configPath := fmt.Sprintf("%s/config", backend)
+	data, err := grafanaCloudSecretBackendConfigData(d)
+	if err != nil {
+		return err
+	}
 	log.Printf("[DEBUG] Writing %q", configPath)
 	if _, err := client.Logical().Write(configPath, data); err != nil {
-		return fmt.Errorf("error writing %q: %s", configPath, err)
+		return vaultError(err, "writing", configPath, "create or update")
 	}
 	log.Printf("[DEBUG] Wrote %q", configPath)
 	return grafanaCloudSecretBackendRead(d, meta)
 }
 
+// checkAdoptedGrafanaCloudMount rejects adopting a mount whose local or
+// seal_wrap differs from the config. Both can only be set when mounting, so
+// adopting it would plan to replace it, unmounting its roles and leases.
+func checkAdoptedGrafanaCloudMount(d *schema.ResourceData, mount *api.MountOutput) error {
+	backend := d.Get("backend").(string)
+	fields := map[string]bool{
+		"local":     mount.Local,
+		"seal_wrap": mount.SealWrap,
+	}
+	for _, k := range []string{"local", "seal_wrap"} {
+		if d.Get(k).(bool) != fields[k] {
+			return fmt.Errorf("cannot adopt mount %q, as its %s is %t but the config sets %t; it can only be changed by unmounting it, so set %s to %t to adopt it", backend, k, fields[k], d.Get(k).(bool), k, fields[k])
+		}
+	}
+	return nil
+}
+
+func grafanaCloudSecretBackendMount(client *api.Client, d *schema.ResourceData) error {
+	backend := d.Get("backend").(string)
+
+	log.Printf("[DEBUG] Mounting grafana-cloud-plugin backend at %q", backend)
+	err := client.Sys().Mount(backend, &api.MountInput{
+		Type:        grafanaCloudPluginType,
+		Description: d.Get("description").(string),
+		Local:       d.Get("local").(bool),
+		SealWrap:    d.Get("seal_wrap").(bool),
+		Options:     expandStringMap(d.Get("options")),
+		Config: api.MountConfigInput{
+			DefaultLeaseTTL:          ttlString(d.Get("default_lease_ttl_seconds").(int)),
+			MaxLeaseTTL:              ttlString(d.Get("max_lease_ttl_seconds").(int)),
+			AuditNonHMACRequestKeys:  expandStringSlice(d.Get("audit_non_hmac_request_keys")),
+			AuditNonHMACResponseKeys: expandStringSlice(d.Get("audit_non_hmac_response_keys")),
+			ListingVisibility:        d.Get("listing_visibility").(string),
+			PluginVersion:            d.Get("plugin_version").(string),
+		},
+	})
+	if err != nil && classifyVaultError(err) == vaultErrorConflict {
+		return fmt.Errorf("%w; set adopt_existing to manage the existing mount", vaultError(err, "mounting", "sys/mounts/"+backend, "create or update"))
+	}
+	if err != nil {
+		return vaultError(err, "mounting", "sys/mounts/"+backend, "create or update")
+	}
+	log.Printf("[DEBUG] Mounted vault grafana cloud backend at %q", backend)
+	return nil
+}
+
 func grafanaCloudSecretBackendDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 	vaultPath := d.Id()
 	log.Printf("[DEBUG] Unmounting vault grafana cloud backend %q", vaultPath)
 
-	err := client.Sys().Unmount(vaultPath)
-	if err != nil && strings.Contains(err.Error(), "Code: 404") {
-		log.Printf("[WARN] %q not found, removing from state", vaultPath)
-		d.SetId("")
-		return fmt.Errorf("error unmounting vault grafana cloud backend from %q: %s", vaultPath, err)
-	} else if err != nil {
-		return fmt.Errorf("error unmounting vault grafana cloud backend from %q: %s", vaultPath, err)
+	if err := client.Sys().Unmount(vaultPath); err != nil && !isNotFound(err) {
+		return vaultError(err, "unmounting", "sys/mounts/"+vaultPath, "delete")
 	}
 	log.Printf("[DEBUG] Unmounted vault grafana cloud backend %q", vaultPath)
 	return nil
 }
 
 func grafanaCloudSecretBackendRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 
 	if err := d.Set("backend", d.Id()); err != nil {
 		return fmt.Errorf("error setting backend: %s", err)
 	}
 
+	// The mount is checked first, as reading the config of a missing mount
+	// is indistinguishable from reading a missing config.
+	log.Printf("[DEBUG] Reading mount %q", d.Id())
+	mount, err := getGrafanaCloudMount(client, d.Id())
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Read mount %q", d.Id())
+	if mount == nil && d.IsNewResource() {
+		return fmt.Errorf("mount %q not found after mounting it", d.Id())
+	}
+	if mount == nil {
+		log.Printf("[WARN] Mount %q not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
 	configPath := fmt.Sprintf("%s/config", d.Id())
 	log.Printf("[DEBUG] Reading %q", configPath)
 
-	resp, err := client.Logical().Read(configPath)
+	resp, err := readAfterWrite(client, configPath, d.IsNewResource())
 	if err != nil {
-		return fmt.Errorf("error reading %q: %s", configPath, err)
+		return vaultError(err, "reading", configPath, "read")
 	}
 	log.Printf("[DEBUG] Read %q", configPath)
 	if resp == nil {
@@ -129,53 +318,255 @@ func grafanaCloudSecretBackendRead(d *schema.ResourceData, meta interface{}) err
 		return nil
 	}
 
-	if val, ok := resp.Data["key"]; ok {
-		if err := d.Set("key", val); err != nil {
-			return fmt.Errorf("error setting state key 'key': %s", err)
+	if err := setGrafanaCloudSecretBackendConfig(d, resp); err != nil {
+		return err
+	}
+
+	mountFields := map[string]interface{}{
+		"description":                  mount.Description,
+		"default_lease_ttl_seconds":    mount.Config.DefaultLeaseTTL,
+		"max_lease_ttl_seconds":        mount.Config.MaxLeaseTTL,
+		"local":                        mount.Local,
+		"seal_wrap":                    mount.SealWrap,
+		"audit_non_hmac_request_keys":  mount.Config.AuditNonHMACRequestKeys,
+		"audit_non_hmac_response_keys": mount.Config.AuditNonHMACResponseKeys,
+		"listing_visibility":           mount.Config.ListingVisibility,
+		"plugin_version":               mount.PluginVersion,
+		"options":                      mount.Options,
+	}
+	for k, v := range mountFields {
+		if err := d.Set(k, v); err != nil {
+			return fmt.Errorf("error setting state key '%s': %s", k, err)
 		}
 	}
-	if val, ok := resp.Data["url"]; ok {
-		if err := d.Set("url", val); err != nil {
-			return fmt.Errorf("error setting state key 'url': %s", err)
+	return nil
+}
+
+// grafanaCloudSecretBackendImport imports a backend by its mount path,
+// optionally prefixed with "<namespace>:". The config, except for the key, is
+// populated by the subsequent Read.
+func grafanaCloudSecretBackendImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	id, err := importNamespace(d)
+	if err != nil {
+		return nil, err
+	}
+	backend := strings.Trim(id, "/")
+	if backend == "" {
+		return nil, fmt.Errorf("invalid backend ID %q: expected the mount path", d.Id())
+	}
+	d.SetId(backend)
+	// defaults are not applied to imported resources
+	if err := d.Set("adopt_existing", false); err != nil {
+		return nil, fmt.Errorf("error setting adopt_existing: %s", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func grafanaCloudSecretBackendUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	// remount rather than replace, so roles and outstanding leases are kept
+	if d.HasChange("backend") {
+		newBackend := d.Get("backend").(string)
+		if err := remount(client, d.Id(), newBackend, d.Timeout(schema.TimeoutUpdate)); err != nil {
+			return err
 		}
+		d.SetId(newBackend)
+	}
+
+	if err := updateGrafanaCloudSecretBackendConfig(client, d); err != nil {
+		return err
 	}
-	if val, ok := resp.Data["organisation"]; ok {
-		if err := d.Set("organisation", val); err != nil {
-			return fmt.Errorf("error setting state key 'organisation': %s", err)
+
+	if d.HasChanges(grafanaCloudSecretBackendTuneFields...) {
+		if err := grafanaCloudSecretBackendTune(client, d); err != nil {
+			return err
 		}
 	}
-	if val, ok := resp.Data["user"]; ok {
-		if err := d.Set("user", val); err != nil {
-			return fmt.Errorf("error setting state key 'user': %s", err)
+	return grafanaCloudSecretBackendRead(d, meta)
+}
+
+// grafanaCloudSecretBackendTune writes the changed mount fields to
+// sys/mounts/<path>/tune. Unlike api.MountConfigInput, this allows the audit
+// keys to be cleared, see tuneStringSlice.
+func grafanaCloudSecretBackendTune(client *api.Client, d *schema.ResourceData) error {
+	tunePath := fmt.Sprintf("sys/mounts/%s/tune", d.Id())
+	data := map[string]interface{}{}
+	if d.HasChange("description") {
+		data["description"] = d.Get("description")
+	}
+	if d.HasChange("default_lease_ttl_seconds") {
+		data["default_lease_ttl"] = ttlStringOrSystem(d.Get("default_lease_ttl_seconds").(int))
+	}
+	if d.HasChange("max_lease_ttl_seconds") {
+		data["max_lease_ttl"] = ttlStringOrSystem(d.Get("max_lease_ttl_seconds").(int))
+	}
+	if d.HasChange("audit_non_hmac_request_keys") {
+		data["audit_non_hmac_request_keys"] = tuneStringSlice(d.Get("audit_non_hmac_request_keys"))
+	}
+	if d.HasChange("audit_non_hmac_response_keys") {
+		data["audit_non_hmac_response_keys"] = tuneStringSlice(d.Get("audit_non_hmac_response_keys"))
+	}
+	if d.HasChange("listing_visibility") {
+		data["listing_visibility"] = d.Get("listing_visibility")
+	}
+	if d.HasChange("plugin_version") {
+		data["plugin_version"] = d.Get("plugin_version")
+	}
+	if d.HasChange("options") {
+		data["options"] = expandStringMap(d.Get("options"))
+	}
+
+	log.Printf("[DEBUG] Tuning mount %q", d.Id())
+	if _, err := client.Logical().Write(tunePath, data); err != nil {
+		return vaultError(err, "writing", tunePath, "update")
+	}
+	log.Printf("[DEBUG] Tuned mount %q", d.Id())
+
+	// a new plugin version only takes effect once the backend is reloaded
+	if d.HasChange("plugin_version") {
+		log.Printf("[DEBUG] Reloading mount %q", d.Id())
+		if _, err := client.Sys().ReloadPlugin(&api.ReloadPluginInput{Mounts: []string{d.Id()}}); err != nil {
+			return vaultError(err, "writing", "sys/plugins/reload/backend", "update and sudo")
 		}
+		log.Printf("[DEBUG] Reloaded mount %q", d.Id())
 	}
 	return nil
 }
 
-func grafanaCloudSecretBackendUpdate(d *schema.ResourceData, meta interface{}) error {
-	backend := d.Id()
+// grafanaCloudSecretBackendConfigFields are the fields of <backend>/config
+// other than the key.
+var grafanaCloudSecretBackendConfigFields = []string{"url", "organisation", "user"}
 
-	client := meta.(*api.Client)
+// grafanaCloudSecretBackendConfigData returns the fields to write to
+// <backend>/config.
+func grafanaCloudSecretBackendConfigData(d *schema.ResourceData) (map[string]interface{}, error) {
 	data := map[string]interface{}{}
+	for _, k := range grafanaCloudSecretBackendConfigFields {
+		data[k] = d.Get(k)
+	}
+	key, err := grafanaCloudSecretBackendKey(d)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		data["key"] = key
+	}
+	return data, nil
+}
 
-	vaultPath := fmt.Sprintf("%s/config", backend)
-	log.Printf("[DEBUG] Updating %q", vaultPath)
-
-	if raw, ok := d.GetOk("key"); ok {
-		data["key"] = raw
+// updateGrafanaCloudSecretBackendConfig sends the changed fields to
+// <backend>/config. The key is only sent when key_version changed, as
+// neither key_wo nor key is stored in the state, so changes to them cannot be
+// detected.
+func updateGrafanaCloudSecretBackendConfig(client *api.Client, d *schema.ResourceData) error {
+	full, err := grafanaCloudSecretBackendConfigData(d)
+	if err != nil {
+		return err
 	}
-	if raw, ok := d.GetOk("url"); ok {
-		data["url"] = raw
+	patch := map[string]interface{}{}
+	for _, k := range grafanaCloudSecretBackendConfigFields {
+		if d.HasChange(k) {
+			patch[k] = full[k]
+		}
 	}
-	if raw, ok := d.GetOk("organisation"); ok {
-		data["organisation"] = raw
+	if d.HasChange("key_version") {
+		patch["key"] = full["key"]
 	}
-	if raw, ok := d.GetOk("user"); ok {
-		data["user"] = raw
+	return patchOrWrite(client, fmt.Sprintf("%s/config", d.Id()), patch, full)
+}
+
+// grafanaCloudSecretBackendKey returns key_wo, which is only present in the
+// config, falling back to the deprecated key. The key is also read from the
+// config, as suppressUnreadKey can leave it out of the plan.
+func grafanaCloudSecretBackendKey(d *schema.ResourceData) (string, error) {
+	for _, k := range []string{"key_wo", "key"} {
+		key, diags := d.GetRawConfigAt(cty.GetAttrPath(k))
+		if diags.HasError() {
+			return "", fmt.Errorf("error reading %s: %s", k, diags[0].Summary)
+		}
+		if key.Type() == cty.String && key.IsKnown() && !key.IsNull() {
+			return key.AsString(), nil
+		}
 	}
-	if _, err := client.Logical().Write(vaultPath, data); err != nil {
-		return fmt.Errorf("error updating template secrets backend role %q: %s", vaultPath, err)
+	return "", nil
+}
+
+// suppressUnreadKey is a schema.SchemaDiffSuppressFunc ignoring a configured
+// key that is missing from the state of an existing backend. The key is never
+// read back from Vault nor stored in the state, so it is assumed to match the
+// key Vault already has; key_version forces it to be written again.
+func suppressUnreadKey(_, old, _ string, d *schema.ResourceData) bool {
+	return old == "" && d.Id() != ""
+}
+
+// setGrafanaCloudSecretBackendConfig sets the config attributes from a
+// response of <backend>/config. The deprecated key is cleared, in the same
+// way as the state upgrade removes it, so that it is never stored in the
+// state.
+func setGrafanaCloudSecretBackendConfig(d *schema.ResourceData, resp *api.Secret) error {
+	if err := d.Set("key", ""); err != nil {
+		return fmt.Errorf("error setting key: %s", err)
+	}
+	for _, k := range grafanaCloudSecretBackendConfigFields {
+		if val, ok := resp.Data[k]; ok {
+			if err := d.Set(k, val); err != nil {
+				return fmt.Errorf("error setting state key '%s': %s", k, err)
+			}
+		}
 	}
-	log.Printf("[DEBUG] Updated %q", vaultPath)
 	return nil
 }
+
+// grafanaCloudSecretBackendStateTypeV1 returns the state type of a backend
+// resource from before key_wo. Its schema is a frozen copy of the schema at
+// the time, so that later changes to the resource do not change it.
+func grafanaCloudSecretBackendStateTypeV1() cty.Type {
+	optional := func(t schema.ValueType) *schema.Schema {
+		return &schema.Schema{Type: t, Optional: true}
+	}
+	required := func(t schema.ValueType) *schema.Schema {
+		return &schema.Schema{Type: t, Required: true}
+	}
+	optionalStrings := func(t schema.ValueType) *schema.Schema {
+		return &schema.Schema{Type: t, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}}
+	}
+	return (&schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Update: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"backend":                      optional(schema.TypeString),
+			"namespace":                    optional(schema.TypeString),
+			"key":                          required(schema.TypeString),
+			"url":                          required(schema.TypeString),
+			"organisation":                 required(schema.TypeString),
+			"user":                         required(schema.TypeString),
+			"description":                  optional(schema.TypeString),
+			"default_lease_ttl_seconds":    optional(schema.TypeInt),
+			"max_lease_ttl_seconds":        optional(schema.TypeInt),
+			"local":                        optional(schema.TypeBool),
+			"seal_wrap":                    optional(schema.TypeBool),
+			"audit_non_hmac_request_keys":  optionalStrings(schema.TypeList),
+			"audit_non_hmac_response_keys": optionalStrings(schema.TypeList),
+			"listing_visibility":           optional(schema.TypeString),
+			"plugin_version":               optional(schema.TypeString),
+			"options":                      optionalStrings(schema.TypeMap),
+			"adopt_existing":               optional(schema.TypeBool),
+		},
+	}).CoreConfigSchema().ImpliedType()
+}
+
+// grafanaCloudSecretBackendRemoveKey removes the key that was stored in the
+// state before key_wo.
+func grafanaCloudSecretBackendRemoveKey(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return nil, nil
+	}
+	log.Printf("[DEBUG] Removing the key of %q from state", rawState["id"])
+	delete(rawState, "key")
+	return rawState, nil
+}
@@ -0,0 +1,177 @@
+package vaultgrafanacloud
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func GrafanaCloudSecretBackendConfigResource() *schema.Resource {
+	return &schema.Resource{
+		Create: grafanaCloudSecretBackendConfigCreate,
+		Delete: grafanaCloudSecretBackendConfigDelete,
+		Read:   grafanaCloudSecretBackendConfigRead,
+		Update: grafanaCloudSecretBackendConfigUpdate,
+		Importer: &schema.ResourceImporter{
+			StateContext: grafanaCloudSecretBackendConfigImport,
+		},
+
+		Schema: grafanaCloudSecretBackendConfigSchema(),
+	}
+}
+
+func grafanaCloudSecretBackendConfigSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"backend": {
+			Type:        schema.TypeString,
+			Default:     "grafana-cloud",
+			Optional:    true,
+			ForceNew:    true,
+			Description: "The mount path of the Grafana Cloud backend to configure, which must already be mounted.",
+			StateFunc:   normaliseBackend,
+		},
+		"namespace": namespaceSchema(),
+		"key": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			ExactlyOneOf:     []string{"key", "key_wo"},
+			Deprecated:       "Use key_wo, which is never stored in the Terraform state, instead",
+			DiffSuppressFunc: suppressUnreadKey,
+			Description:      "API key with Admin role to create user keys",
+		},
+		"key_wo": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			WriteOnly:   true,
+			Description: "API key with Admin role to create user keys, which is never stored in the Terraform state. Requires Terraform 1.11 or later",
+		},
+		"key_version": {
+			Type:        schema.TypeInt,
+			Optional:    true,
+			Description: "Version of the key. Changing it writes key_wo or key to the backend again",
+		},
+		"url": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The URL for the Grafana Cloud API",
+		},
+		"organisation": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The Organisation slug for the Grafana Cloud API",
+		},
+		"user": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The User that is needed to interact with prometheus, if set this is returned alongside every issued credential",
+		},
+	}
+}
+
+func grafanaCloudSecretBackendConfigCreate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+	backend := normaliseBackend(d.Get("backend"))
+
+	log.Printf("[DEBUG] Reading mount %q", backend)
+	mount, err := getGrafanaCloudMount(client, backend)
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Read mount %q", backend)
+	if mount == nil {
+		return fmt.Errorf("no Grafana Cloud backend is mounted at %q", backend)
+	}
+
+	configPath := fmt.Sprintf("%s/config", backend)
+	data, err := grafanaCloudSecretBackendConfigData(d)
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Writing %q", configPath)
+	if _, err := client.Logical().Write(configPath, data); err != nil {
+		return vaultError(err, "writing", configPath, "create or update")
+	}
+	log.Printf("[DEBUG] Wrote %q", configPath)
+	d.SetId(backend)
+	return grafanaCloudSecretBackendConfigRead(d, meta)
+}
+
+func grafanaCloudSecretBackendConfigRead(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	if err := d.Set("backend", d.Id()); err != nil {
+		return fmt.Errorf("error setting backend: %s", err)
+	}
+
+	log.Printf("[DEBUG] Reading mount %q", d.Id())
+	mount, err := getGrafanaCloudMount(client, d.Id())
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Read mount %q", d.Id())
+	if mount == nil {
+		log.Printf("[WARN] Mount %q not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	configPath := fmt.Sprintf("%s/config", d.Id())
+	log.Printf("[DEBUG] Reading %q", configPath)
+	resp, err := readAfterWrite(client, configPath, d.IsNewResource())
+	if err != nil {
+		return vaultError(err, "reading", configPath, "read")
+	}
+	log.Printf("[DEBUG] Read %q", configPath)
+	if resp == nil {
+		log.Printf("[WARN] %q not found, removing from state", configPath)
+		d.SetId("")
+		return nil
+	}
+	return setGrafanaCloudSecretBackendConfig(d, resp)
+}
+
+func grafanaCloudSecretBackendConfigUpdate(d *schema.ResourceData, meta interface{}) error {
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+
+	if err := updateGrafanaCloudSecretBackendConfig(client, d); err != nil {
+		return err
+	}
+	return grafanaCloudSecretBackendConfigRead(d, meta)
+}
+
+// grafanaCloudSecretBackendConfigDelete only removes the config from state.
+// The plugin cannot delete its config, and the mount belongs to whoever
+// mounted it.
+func grafanaCloudSecretBackendConfigDelete(d *schema.ResourceData, _ interface{}) error {
+	log.Printf("[DEBUG] Leaving %q mounted and configured", d.Id())
+	return nil
+}
+
+// grafanaCloudSecretBackendConfigImport imports the config of a backend by
+// its mount path, optionally prefixed with "<namespace>:".
+func grafanaCloudSecretBackendConfigImport(_ context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+	id, err := importNamespace(d)
+	if err != nil {
+		return nil, err
+	}
+	backend := strings.Trim(id, "/")
+	if backend == "" {
+		return nil, fmt.Errorf("invalid backend config ID %q: expected the mount path", d.Id())
+	}
+	d.SetId(backend)
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,81 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestGrafanaCloudSecretBackendConfig(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testProviders,
+		PreCheck:     func() { testutil.TestAccPreCheck(t) },
+		CheckDestroy: testAccGrafanaCloudSecretBackendConfigCheckDestroy(t, backend),
+		Steps: []resource.TestStep{
+			{
+				Config:      testGrafanaCloudSecretBackendConfig_config(backend, "test_org"),
+				ExpectError: regexp.MustCompile("no Grafana Cloud backend is mounted"),
+			},
+			{
+				PreConfig: testAccGrafanaCloudSecretBackendMount(t, backend, grafanaCloudPluginType),
+				Config:    testGrafanaCloudSecretBackendConfig_config(backend, "test_org"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend_config.test", "backend", backend),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend_config.test", "url", "http://localhost"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend_config.test", "organisation", "test_org"),
+				),
+			},
+			{
+				Config: testGrafanaCloudSecretBackendConfig_config(backend, "updated_org"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend_config.test", "organisation", "updated_org"),
+				),
+			},
+			{
+				ResourceName:            "vaultgrafanacloud_secret_backend_config.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"key"},
+			},
+		},
+	})
+}
+
+// testAccGrafanaCloudSecretBackendConfigCheckDestroy checks destroying the
+// config left the backend mounted, then unmounts the backend mounted by the
+// test.
+func testAccGrafanaCloudSecretBackendConfigCheckDestroy(t *testing.T, backend string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testAccClient(t)
+		mount, err := getMount(client, backend)
+		if err != nil {
+			return err
+		}
+		if mount == nil {
+			return fmt.Errorf("expected %q to still be mounted", backend)
+		}
+		if err := client.Sys().Unmount(backend); err != nil {
+			return fmt.Errorf("error unmounting %q: %s", backend, err)
+		}
+		return nil
+	}
+}
+
+func testGrafanaCloudSecretBackendConfig_config(backend, organisation string) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend_config" "test" {
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "%s"
+	user = "user"
+}
+`, backend, organisation)
+}
@@ -1,7 +1,9 @@
 package vaultgrafanacloud
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -9,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/hashicorp/vault/api"
 )
@@ -30,7 +33,8 @@ func TestGrafanaCloudSecretBackend(t *testing.T) {
 				Config: testGrafanaCloudSecretBackend_initialConfig(backend, key, url, organisation, user),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "backend", backend),
-					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "key", key),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "key", ""),
+					testAccGrafanaCloudSecretBackendKey(t, backend, key),
 					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "url", url),
 					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "organisation", organisation),
 					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "user", user),
@@ -40,18 +44,73 @@ func TestGrafanaCloudSecretBackend(t *testing.T) {
 				Config: testGrafanaCloudSecretBackend_updateConfig(backend, key, url, organisation, user),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "backend", backend),
-					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "key", key),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "key", ""),
+					testAccGrafanaCloudSecretBackendKey(t, backend, key),
 					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "url", url),
 					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "organisation", organisation),
 					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "user", user),
 				),
 			},
+			{
+				ResourceName:      "vaultgrafanacloud_secret_backend.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the key is never read back from Vault
+				ImportStateVerifyIgnore: []string{"key"},
+			},
+			{
+				ResourceName:       "vaultgrafanacloud_secret_backend.test",
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+			{
+				// the key missing from the imported state is not a diff
+				Config:   testGrafanaCloudSecretBackend_updateConfig(backend, key, url, organisation, user),
+				PlanOnly: true,
+			},
 		},
 	})
 }
 
+func TestSuppressUnreadKey(t *testing.T) {
+	d := GrafanaCloudSecretBackendResource().TestResourceData()
+	if suppressUnreadKey("key", "", "secret", d) {
+		t.Error("expected the key of a new backend not to be suppressed")
+	}
+	d.SetId("grafana-cloud")
+	if !suppressUnreadKey("key", "", "secret", d) {
+		t.Error("expected a key missing from the state to be suppressed")
+	}
+	if suppressUnreadKey("key", "old", "secret", d) {
+		t.Error("expected a changed key to not be suppressed")
+	}
+}
+
+func TestSetGrafanaCloudSecretBackendConfig(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, grafanaCloudSecretBackendSchema(), map[string]interface{}{
+		"key":          "secret",
+		"url":          "http://localhost",
+		"organisation": "test_org",
+		"user":         "user",
+	})
+	resp := &api.Secret{Data: map[string]interface{}{
+		"url":          "http://localhost",
+		"organisation": "updated_org",
+		"user":         "user",
+	}}
+	if err := setGrafanaCloudSecretBackendConfig(d, resp); err != nil {
+		t.Fatal(err)
+	}
+	if key := d.Get("key").(string); key != "" {
+		t.Errorf("expected the deprecated key not to be stored, got %q", key)
+	}
+	if organisation := d.Get("organisation").(string); organisation != "updated_org" {
+		t.Errorf("expected organisation %q, got %q", "updated_org", organisation)
+	}
+}
+
 func testAccGrafanaCloudSecretBackendCheckDestroy(s *terraform.State) error {
-	client := testProvider.Meta().(*api.Client)
+	client := testProvider.Meta().(*providerMeta).client
 
 	mounts, err := client.Sys().ListMounts()
 	if err != nil {
@@ -94,3 +153,460 @@ resource "vaultgrafanacloud_secret_backend" "test" {
 	user = "%s"
 }`, backend, key, url, organisation, user)
 }
+
+func TestGrafanaCloudSecretBackend_keyWriteOnly(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	key := uuid.New().String()
+	rotatedKey := uuid.New().String()
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretBackend_keyWriteOnlyConfig(backend, key, 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("vaultgrafanacloud_secret_backend.test", "key"),
+					resource.TestCheckNoResourceAttr("vaultgrafanacloud_secret_backend.test", "key_wo"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "key_version", "1"),
+					testAccGrafanaCloudSecretBackendKey(t, backend, key),
+				),
+			},
+			{
+				// a new key without a new key_version is not detected
+				Config: testGrafanaCloudSecretBackend_keyWriteOnlyConfig(backend, rotatedKey, 1),
+				Check:  testAccGrafanaCloudSecretBackendKey(t, backend, key),
+			},
+			{
+				Config: testGrafanaCloudSecretBackend_keyWriteOnlyConfig(backend, rotatedKey, 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("vaultgrafanacloud_secret_backend.test", "key"),
+					resource.TestCheckNoResourceAttr("vaultgrafanacloud_secret_backend.test", "key_wo"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "key_version", "2"),
+					testAccGrafanaCloudSecretBackendKey(t, backend, rotatedKey),
+				),
+			},
+		},
+	})
+}
+
+// testAccGrafanaCloudSecretBackendKey checks the key Vault has for the
+// backend, which the provider itself never reads.
+func testAccGrafanaCloudSecretBackendKey(t *testing.T, backend, key string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		configPath := fmt.Sprintf("%s/config", backend)
+		resp, err := testAccClient(t).Logical().Read(configPath)
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			return fmt.Errorf("%q not found", configPath)
+		}
+		if resp.Data["key"] != key {
+			return fmt.Errorf("expected %q to have the key written with key_version", configPath)
+		}
+		return nil
+	}
+}
+
+func testGrafanaCloudSecretBackend_keyWriteOnlyConfig(backend, key string, keyVersion int) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key_wo = "%s"
+	key_version = %d
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+}`, backend, key, keyVersion)
+}
+
+func TestGrafanaCloudSecretBackendRemoveKey(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":      "grafana-cloud",
+		"backend": "grafana-cloud",
+		"key":     "secret",
+		"url":     "http://localhost",
+	}
+	got, err := grafanaCloudSecretBackendRemoveKey(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got["key"]; ok {
+		t.Errorf("expected the key to be removed, got %v", got)
+	}
+	if got["url"] != "http://localhost" {
+		t.Errorf("expected the url to be kept, got %v", got)
+	}
+}
+
+func TestGrafanaCloudSecretBackendStateTypeV1(t *testing.T) {
+	ty := grafanaCloudSecretBackendStateTypeV1()
+	if !ty.HasAttribute("key") {
+		t.Error("expected the version 1 state to have the key")
+	}
+	for _, k := range []string{"key_wo", "key_version"} {
+		if ty.HasAttribute(k) {
+			t.Errorf("expected the version 1 state not to have %s", k)
+		}
+	}
+}
+
+func TestGrafanaCloudSecretBackend_namespace(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	namespace := acctest.RandomWithPrefix("tf-test-ns")
+	key := uuid.New().String()
+	url := "http://localhost"
+	organisation := "test_org"
+	user := "user"
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck: func() {
+			testutil.TestEntPreCheck(t)
+			testAccNamespace(t, namespace)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretBackend_namespaceConfig(namespace, backend, key, url, organisation, user),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "namespace", namespace),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "backend", backend),
+				),
+			},
+			{
+				ResourceName:            "vaultgrafanacloud_secret_backend.test",
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("%s:%s", namespace, backend),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"key"},
+			},
+		},
+	})
+}
+
+// testAccNamespace creates a child namespace, removing it once the test ends.
+func testAccNamespace(t *testing.T, namespace string) {
+	t.Helper()
+	client := testAccClient(t)
+	path := fmt.Sprintf("sys/namespaces/%s", namespace)
+	if _, err := client.Logical().Write(path, nil); err != nil {
+		t.Fatalf("error creating namespace %q: %s", namespace, err)
+	}
+	t.Cleanup(func() {
+		if _, err := client.Logical().Delete(path); err != nil {
+			t.Errorf("error deleting namespace %q: %s", namespace, err)
+		}
+	})
+}
+
+func testGrafanaCloudSecretBackend_namespaceConfig(namespace, backend, key, url, organisation, user string) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "test" {
+	namespace = "%s"
+	backend = "%s"
+	key = "%s"
+	url = "%s"
+	organisation = "%s"
+	user = "%s"
+}`, namespace, backend, key, url, organisation, user)
+}
+
+func TestGrafanaCloudSecretBackend_mountTuning(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	var accessor string
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testProviders,
+		PreCheck:     func() { testutil.TestAccPreCheck(t) },
+		CheckDestroy: testAccGrafanaCloudSecretBackendCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretBackend_mountConfig(backend, "initial", 3600, 7200, `["role"]`, "hidden"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "description", "initial"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "default_lease_ttl_seconds", "3600"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "max_lease_ttl_seconds", "7200"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "audit_non_hmac_request_keys.#", "1"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "audit_non_hmac_request_keys.0", "role"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "listing_visibility", "hidden"),
+					testAccGrafanaCloudSecretBackendAccessor(backend, &accessor),
+				),
+			},
+			{
+				Config: testGrafanaCloudSecretBackend_mountConfig(backend, "updated", 1800, 3600, `[]`, "unauth"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "description", "updated"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "default_lease_ttl_seconds", "1800"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "max_lease_ttl_seconds", "3600"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "audit_non_hmac_request_keys.#", "0"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "listing_visibility", "unauth"),
+					testAccGrafanaCloudSecretBackendAccessor(backend, &accessor),
+				),
+			},
+		},
+	})
+}
+
+func TestGrafanaCloudSecretBackend_unmounted(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	name := uuid.New().String()
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testProviders,
+		PreCheck:     func() { testutil.TestAccPreCheck(t) },
+		CheckDestroy: testAccGrafanaCloudSecretBackendCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretBackend_remountConfig(backend, name),
+			},
+			{
+				// The backend and its role are recreated once unmounted
+				PreConfig:          testAccGrafanaCloudSecretBackendUnmount(t, backend, ""),
+				Config:             testGrafanaCloudSecretBackend_remountConfig(backend, name),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testGrafanaCloudSecretBackend_remountConfig(backend, name),
+			},
+			{
+				// Something else mounted in its place is left alone
+				PreConfig:   testAccGrafanaCloudSecretBackendUnmount(t, backend, "kv"),
+				Config:      testGrafanaCloudSecretBackend_remountConfig(backend, name),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`is a "kv" secrets engine`),
+			},
+			{
+				PreConfig: testAccGrafanaCloudSecretBackendUnmount(t, backend, ""),
+				Config:    testGrafanaCloudSecretBackend_remountConfig(backend, name),
+			},
+		},
+	})
+}
+
+func TestGrafanaCloudSecretBackend_adoptExisting(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	kvBackend := acctest.RandomWithPrefix("tf-test-kv")
+	var accessor string
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testProviders,
+		PreCheck:     func() { testutil.TestAccPreCheck(t) },
+		CheckDestroy: testAccGrafanaCloudSecretBackendCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				PreConfig:   testAccGrafanaCloudSecretBackendMount(t, backend, grafanaCloudPluginType),
+				Config:      testGrafanaCloudSecretBackend_adoptConfig("test", backend, false),
+				ExpectError: regexp.MustCompile("set adopt_existing to manage the existing mount"),
+			},
+			{
+				Config: testGrafanaCloudSecretBackend_adoptConfig("test", backend, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "backend", backend),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "description", "adopted"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "url", "http://localhost"),
+					testAccGrafanaCloudSecretBackendAccessor(backend, &accessor),
+				),
+			},
+			{
+				PreConfig:   testAccGrafanaCloudSecretBackendMount(t, kvBackend, "kv"),
+				Config:      testGrafanaCloudSecretBackend_adoptConfig("test", backend, true) + testGrafanaCloudSecretBackend_adoptConfig("kv", kvBackend, true),
+				ExpectError: regexp.MustCompile(`is a "kv" secrets engine`),
+			},
+		},
+	})
+}
+
+// testAccGrafanaCloudSecretBackendMount mounts a secrets engine of mountType
+// outside of Terraform, unmounting it once the test completes unless
+// Terraform already has.
+func testAccGrafanaCloudSecretBackendMount(t *testing.T, backend, mountType string) func() {
+	return func() {
+		client := testAccClient(t)
+		if err := client.Sys().Mount(backend, &api.MountInput{Type: mountType}); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if err := client.Sys().Unmount(backend); err != nil && !isNotFound(err) {
+				t.Errorf("error unmounting %q: %s", backend, err)
+			}
+		})
+	}
+}
+
+func TestGrafanaCloudSecretBackend_adoptMismatch(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				PreConfig: testAccGrafanaCloudSecretBackendMount(t, backend, grafanaCloudPluginType),
+				Config: fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+	seal_wrap = true
+	adopt_existing = true
+}`, backend),
+				ExpectError: regexp.MustCompile(`cannot adopt mount .* seal_wrap is false`),
+			},
+		},
+	})
+}
+
+func TestCheckAdoptedGrafanaCloudMount(t *testing.T) {
+	tests := map[string]struct {
+		raw     map[string]interface{}
+		mount   *api.MountOutput
+		wantErr bool
+	}{
+		"matching": {
+			raw:   map[string]interface{}{"local": true},
+			mount: &api.MountOutput{Local: true},
+		},
+		"local differs": {
+			raw:     map[string]interface{}{"local": true},
+			mount:   &api.MountOutput{},
+			wantErr: true,
+		},
+		"seal_wrap differs": {
+			raw:     map[string]interface{}{},
+			mount:   &api.MountOutput{SealWrap: true},
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := schema.TestResourceDataRaw(t, grafanaCloudSecretBackendSchema(), tt.raw)
+			err := checkAdoptedGrafanaCloudMount(d, tt.mount)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("expected error %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func testGrafanaCloudSecretBackend_adoptConfig(name, backend string, adopt bool) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "%[1]s" {
+	backend = "%[2]s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+	description = "adopted"
+	adopt_existing = %[3]t
+}
+`, name, backend, adopt)
+}
+
+// testAccGrafanaCloudSecretBackendUnmount unmounts the backend outside of
+// Terraform, mounting a secrets engine of mountType in its place if set.
+func testAccGrafanaCloudSecretBackendUnmount(t *testing.T, backend, mountType string) func() {
+	return func() {
+		client := testProvider.Meta().(*providerMeta).client
+		if err := client.Sys().Unmount(backend); err != nil {
+			t.Fatal(err)
+		}
+		if mountType == "" {
+			return
+		}
+		if err := client.Sys().Mount(backend, &api.MountInput{Type: mountType}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// testAccGrafanaCloudSecretBackendAccessor records the accessor of the mount,
+// or checks it is unchanged if already recorded, i.e. the mount was tuned in
+// place rather than remounted.
+func testAccGrafanaCloudSecretBackendAccessor(backend string, accessor *string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		client := testProvider.Meta().(*providerMeta).client
+		mount, err := getMount(client, backend)
+		if err != nil {
+			return err
+		}
+		if mount == nil {
+			return fmt.Errorf("mount %q not found", backend)
+		}
+		if *accessor != "" && *accessor != mount.Accessor {
+			return fmt.Errorf("expected mount accessor %q, got %q", *accessor, mount.Accessor)
+		}
+		*accessor = mount.Accessor
+		return nil
+	}
+}
+
+func testGrafanaCloudSecretBackend_mountConfig(backend, description string, defaultTTL, maxTTL int, auditKeys, listingVisibility string) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+
+	description = "%s"
+	default_lease_ttl_seconds = %d
+	max_lease_ttl_seconds = %d
+	audit_non_hmac_request_keys = %s
+	listing_visibility = "%s"
+}`, backend, description, defaultTTL, maxTTL, auditKeys, listingVisibility)
+}
+
+func TestGrafanaCloudSecretBackend_remount(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	newBackend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	name := uuid.New().String()
+	var accessor string
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testProviders,
+		PreCheck:     func() { testutil.TestAccPreCheck(t) },
+		CheckDestroy: testAccGrafanaCloudSecretBackendCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretBackend_remountConfig(backend, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "backend", backend),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "id", fmt.Sprintf("%s/roles/%s", backend, name)),
+					testAccGrafanaCloudSecretBackendAccessor(backend, &accessor),
+				),
+			},
+			{
+				Config: testGrafanaCloudSecretBackend_remountConfig(newBackend, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "id", newBackend),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_backend.test", "backend", newBackend),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "id", fmt.Sprintf("%s/roles/%s", newBackend, name)),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "backend", newBackend),
+					testAccGrafanaCloudSecretBackendAccessor(newBackend, &accessor),
+				),
+			},
+		},
+	})
+}
+
+func testGrafanaCloudSecretBackend_remountConfig(backend, name string) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+}
+
+resource "vaultgrafanacloud_secret_role" "test" {
+	backend = vaultgrafanacloud_secret_backend.test.backend
+	name = "%s"
+	gc_role = "Viewer"
+}`, backend, name)
+}
@@ -1,68 +1,148 @@
 package vaultgrafanacloud
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
-	"regexp"
+	"net/url"
+	"strconv"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/vault/api"
 )
 
-var (
-	gcSecretFromPathRegex         = regexp.MustCompile("^(.+)/roles/.+$")
-	gcSecretRoleNameFromPathRegex = regexp.MustCompile("^.+/roles/(.+$)")
-)
+// grafanaCloudRoles are the Grafana Cloud roles the plugin can issue keys
+// for.
+var grafanaCloudRoles = []string{"Viewer", "Editor", "Admin", "MetricsPublisher", "PluginPublisher"}
 
 func GrafanaCloudSecretRoleResource() *schema.Resource {
 	return &schema.Resource{
-		SchemaVersion: 1,
+		SchemaVersion: 3,
 		Create:        grafanaCloudSecretRoleCreate,
 		Delete:        grafanaCloudSecretRoleDelete,
 		Read:          grafanaCloudSecretRoleRead,
 		Update:        grafanaCloudSecretRoleUpdate,
-
-		Schema: map[string]*schema.Schema{
-			"backend": {
-				Type:        schema.TypeString,
-				Default:     "grafana-cloud",
-				Optional:    true,
-				ForceNew:    true,
-				Description: "The mount path of the Grafana Cloud backend.",
-				StateFunc: func(v interface{}) string {
-					return strings.Trim(v.(string), "/")
-				},
-			},
-			"name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "The name for the role",
-			},
-			"gc_role": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The Grafana Cloud role, i.e. the key authorization level",
-			},
-			"ttl_seconds": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     300,
-				Description: "Default lease for generated credentials in seconds",
+		CustomizeDiff: grafanaCloudSecretRoleCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: grafanaCloudSecretRoleImport,
+		},
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 1,
+				Type:    grafanaCloudSecretRoleStateTypeV1(),
+				Upgrade: grafanaCloudSecretRoleStateUpgradeV1,
 			},
-			"max_ttl_seconds": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     300,
-				Description: "Maximum time for role in seconds",
+			{
+				Version: 2,
+				Type:    grafanaCloudSecretRoleStateTypeV2(),
+				Upgrade: grafanaCloudSecretRoleStateUpgradeV2,
 			},
 		},
+
+		Schema: grafanaCloudSecretRoleSchema(),
+	}
+}
+
+func grafanaCloudSecretRoleSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"backend": {
+			Type:        schema.TypeString,
+			Default:     "grafana-cloud",
+			Optional:    true,
+			Description: "The mount path of the Grafana Cloud backend. Follows the backend when it is remounted.",
+			StateFunc:   normaliseBackend,
+		},
+		"namespace": namespaceSchema(),
+		"name": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The name for the role",
+		},
+		"gc_role": {
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateFunc:     validation.StringInSlice(grafanaCloudRoles, true),
+			DiffSuppressFunc: suppressCaseDiff,
+			Description:      "The Grafana Cloud role, i.e. the key authorization level, one of " + strings.Join(grafanaCloudRoles, ", "),
+		},
+		"ttl": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"ttl_seconds"},
+			ValidateFunc:  validateDurationSeconds,
+			StateFunc:     normaliseDurationSeconds,
+			Description:   `Default lease for generated credentials, as a duration such as "1h30m" or "1d", or a number of seconds, "0" uses the mount default. Defaults to "300"`,
+		},
+		"max_ttl": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"max_ttl_seconds"},
+			ValidateFunc:  validateDurationSeconds,
+			StateFunc:     normaliseDurationSeconds,
+			Description:   `Maximum time for role, as a duration such as "1h30m" or "1d", or a number of seconds, "0" uses the mount default. Defaults to "300"`,
+		},
+		"ttl_seconds": {
+			Type:          schema.TypeInt,
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"ttl"},
+			Deprecated:    "Use ttl instead",
+			Description:   "Default lease for generated credentials in seconds, 0 uses the mount default",
+		},
+		"max_ttl_seconds": {
+			Type:          schema.TypeInt,
+			Optional:      true,
+			Computed:      true,
+			ConflictsWith: []string{"max_ttl"},
+			Deprecated:    "Use max_ttl instead",
+			Description:   "Maximum time for role in seconds, 0 uses the mount default",
+		},
+		"strict_ttls": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Fail the plan, rather than warn, when ttl or max_ttl exceed the mount's default or max lease TTL",
+		},
+		"effective_ttl_seconds": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The default lease of generated credentials in seconds, after applying the mount's lease TTLs",
+		},
+		"effective_max_ttl_seconds": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The maximum lease of generated credentials in seconds, after applying the mount's lease TTLs",
+		},
+		"mount_default_lease_ttl_seconds": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The default lease TTL of the backend's mount in seconds",
+		},
+		"mount_max_lease_ttl_seconds": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The max lease TTL of the backend's mount in seconds",
+		},
+		"mount_accessor": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The accessor of the backend's mount, used to find the backend after it is remounted",
+		},
 	}
 }
 
 func grafanaCloudSecretRoleCreate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
 	backend := d.Get("backend").(string)
 	name := d.Get("name").(string)
 	rolePath := fmt.Sprintf("%s/roles/%s", backend, name)
@@ -70,65 +150,88 @@ func grafanaCloudSecretRoleCreate(d *schema.ResourceData, meta interface{}) erro
 	log.Printf("[DEBUG] Creating %q", rolePath)
 
 	data := map[string]interface{}{}
-	if v, ok := d.GetOkExists("gc_role"); ok {
-		data["gc_role"] = v
-	}
-	if v, ok := d.GetOkExists("ttl_seconds"); ok {
-		data["ttl_seconds"] = v
-	}
-	if v, ok := d.GetOkExists("max_ttl_seconds"); ok {
-		data["max_ttl_seconds"] = v
+	for _, k := range grafanaCloudSecretRoleFields {
+		data[k] = grafanaCloudSecretRoleField(d, k)
 	}
 
 	log.Printf("[DEBUG] Writing %q", rolePath)
 	if _, err := client.Logical().Write(rolePath, data); err != nil {
-		return fmt.Errorf("error writing %q: %s", rolePath, err)
+		return vaultError(err, "writing", rolePath, "create")
 	}
-	d.SetId(rolePath)
+	d.SetId(grafanaCloudSecretRoleID(backend, name))
 	log.Printf("[DEBUG] Wrote %q", rolePath)
 	return grafanaCloudSecretRoleRead(d, meta)
 }
 
 func grafanaCloudSecretRoleDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
-	rolePath := d.Id()
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+	backend, name, err := parseGrafanaCloudSecretRoleID(d.Id())
+	if err != nil {
+		return err
+	}
+	rolePath := fmt.Sprintf("%s/roles/%s", backend, name)
 	log.Printf("[DEBUG] Deleting %q", rolePath)
 
-	if _, err := client.Logical().Delete(rolePath); err != nil && !strings.Contains(err.Error(), "Code: 404") {
-		return fmt.Errorf("error deleting %q: %s", rolePath, err)
-	} else if err != nil {
-		log.Printf("[DEBUG] %q not found, removing from state", rolePath)
-		d.SetId("")
-		return nil
+	if _, err := client.Logical().Delete(rolePath); err != nil && !isNotFound(err) {
+		return vaultError(err, "deleting", rolePath, "delete")
 	}
-	log.Printf("[DEBUG] Deleted template auth backend role %q", rolePath)
+	log.Printf("[DEBUG] Deleted %q", rolePath)
 	return nil
 }
 
 func grafanaCloudSecretRoleRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
-	rolePath := d.Id()
-	log.Printf("[DEBUG] Reading %q", rolePath)
-
-	roleName, err := gcSecretRoleNameFromPath(rolePath)
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+	backend, roleName, err := parseGrafanaCloudSecretRoleID(d.Id())
 	if err != nil {
-		return fmt.Errorf("invalid role ID %q: %s", rolePath, err)
+		return err
 	}
+	rolePath := fmt.Sprintf("%s/roles/%s", backend, roleName)
+	log.Printf("[DEBUG] Reading %q", rolePath)
+
 	if err := d.Set("name", roleName); err != nil {
 		return fmt.Errorf("error setting name: %s", err)
 	}
+	if err := d.Set("backend", backend); err != nil {
+		return fmt.Errorf("error setting backend: %s", err)
+	}
 
-	backend, err := gcSecretFromPath(rolePath)
+	log.Printf("[DEBUG] Reading mount %q", backend)
+	mount, err := getGrafanaCloudMount(client, backend)
 	if err != nil {
-		return fmt.Errorf("invalid role ID %q: %s", rolePath, err)
+		return err
 	}
-	if err := d.Set("backend", backend); err != nil {
-		return fmt.Errorf("error setting backend: %s", err)
+	log.Printf("[DEBUG] Read mount %q", backend)
+	if mount == nil && !d.IsNewResource() {
+		// The backend may have been remounted by something other than this
+		// role's backend attribute, e.g. a backend resource in another state,
+		// in which case the role moved along with it.
+		newBackend, newMount, err := getGrafanaCloudSecretRoleRemountedBackend(client, d.Get("mount_accessor").(string))
+		if err != nil {
+			return err
+		}
+		if newMount == nil {
+			log.Printf("[WARN] Mount %q of %q not found, removing from state", backend, rolePath)
+			d.SetId("")
+			return nil
+		}
+		log.Printf("[DEBUG] Mount %q of %q was remounted at %q", backend, rolePath, newBackend)
+		backend, mount = newBackend, newMount
+		rolePath = fmt.Sprintf("%s/roles/%s", backend, roleName)
+		d.SetId(grafanaCloudSecretRoleID(backend, roleName))
+		if err := d.Set("backend", backend); err != nil {
+			return fmt.Errorf("error setting backend: %s", err)
+		}
 	}
 
-	resp, err := client.Logical().Read(rolePath)
+	resp, err := readAfterWrite(client, rolePath, d.IsNewResource())
 	if err != nil {
-		return fmt.Errorf("error reading %q: %s", rolePath, err)
+		return vaultError(err, "reading", rolePath, "read")
 	}
 	log.Printf("[DEBUG] Read %q", rolePath)
 
@@ -138,66 +241,588 @@ func grafanaCloudSecretRoleRead(d *schema.ResourceData, meta interface{}) error
 		return nil
 	}
 
-	if val, ok := resp.Data["gc_role"]; ok {
-		if err := d.Set("gc_role", val); err != nil {
-			return fmt.Errorf("error setting state key 'gc_role': %s", err)
-		}
+	if err := setGrafanaCloudSecretRole(d, resp); err != nil {
+		return err
+	}
+	if mount == nil {
+		return nil
 	}
+	if err := d.Set("mount_accessor", mount.Accessor); err != nil {
+		return fmt.Errorf("error setting mount_accessor: %s", err)
+	}
+	return setGrafanaCloudSecretRoleEffectiveTTLs(d, client, backend)
+}
 
-	if val, ok := resp.Data["ttl_seconds"]; ok {
-		if err := d.Set("ttl_seconds", val); err != nil {
-			return fmt.Errorf("error setting state key 'ttl_seconds': %s", err)
+// getGrafanaCloudSecretRoleRemountedBackend returns the path the Grafana
+// Cloud backend with the given accessor is mounted at now, and the mount, or
+// nil if there is none. Roles read before the accessor was recorded have none
+// to look for.
+func getGrafanaCloudSecretRoleRemountedBackend(client *api.Client, accessor string) (string, *api.MountOutput, error) {
+	if accessor == "" {
+		return "", nil, nil
+	}
+	log.Printf("[DEBUG] Reading the mount with accessor %q", accessor)
+	path, mount, err := getMountByAccessor(client, accessor)
+	if err != nil {
+		return "", nil, err
+	}
+	log.Printf("[DEBUG] Read the mount with accessor %q", accessor)
+	if mount == nil || mount.Type != grafanaCloudPluginType {
+		return "", nil, nil
+	}
+	return path, mount, nil
+}
+
+// setGrafanaCloudSecretRoleEffectiveTTLs sets the lease TTLs of the role's
+// mount, and the effective TTLs from them. They are informational, so a token
+// that cannot read the mount's tuning leaves them as they are.
+func setGrafanaCloudSecretRoleEffectiveTTLs(d *schema.ResourceData, client *api.Client, backend string) error {
+	tunePath := fmt.Sprintf("sys/mounts/%s/tune", backend)
+	log.Printf("[DEBUG] Reading %q", tunePath)
+	tune, err := client.Sys().MountConfig(backend)
+	if err != nil && classifyVaultError(err) == vaultErrorPermissionDenied {
+		log.Printf("[WARN] %s, leaving the effective TTLs of %q unset", vaultError(err, "reading", tunePath, "read"), d.Id())
+		return nil
+	}
+	if err != nil {
+		return vaultError(err, "reading", tunePath, "read")
+	}
+	log.Printf("[DEBUG] Read %q", tunePath)
+
+	ttl, maxTTL := effectiveGrafanaCloudSecretRoleTTLs(d.Get("ttl_seconds").(int), d.Get("max_ttl_seconds").(int), tune)
+	if err := d.Set("effective_ttl_seconds", ttl); err != nil {
+		return fmt.Errorf("error setting effective_ttl_seconds: %s", err)
+	}
+	if err := d.Set("effective_max_ttl_seconds", maxTTL); err != nil {
+		return fmt.Errorf("error setting effective_max_ttl_seconds: %s", err)
+	}
+	if err := d.Set("mount_default_lease_ttl_seconds", tune.DefaultLeaseTTL); err != nil {
+		return fmt.Errorf("error setting mount_default_lease_ttl_seconds: %s", err)
+	}
+	if err := d.Set("mount_max_lease_ttl_seconds", tune.MaxLeaseTTL); err != nil {
+		return fmt.Errorf("error setting mount_max_lease_ttl_seconds: %s", err)
+	}
+	return nil
+}
+
+// grafanaCloudSecretRoleFields are the fields of <backend>/roles/<name>.
+var grafanaCloudSecretRoleFields = []string{"gc_role", "ttl_seconds", "max_ttl_seconds"}
+
+// grafanaCloudSecretRoleField returns the value of a role field to write to
+// <backend>/roles/<name>, with gc_role in the casing the plugin expects, as
+// it is configured case insensitively.
+func grafanaCloudSecretRoleField(d *schema.ResourceData, k string) interface{} {
+	if k != "gc_role" {
+		return d.Get(k)
+	}
+	role := d.Get(k).(string)
+	for _, r := range grafanaCloudRoles {
+		if strings.EqualFold(r, role) {
+			return r
 		}
 	}
+	return role
+}
 
-	if val, ok := resp.Data["max_ttl_seconds"]; ok {
-		if err := d.Set("max_ttl_seconds", val); err != nil {
-			return fmt.Errorf("error setting state key 'max_ttl_seconds': %s", err)
+// grafanaCloudSecretRoleTTLAliases maps the duration attributes of a role to
+// the deprecated attributes in seconds they replace, which are kept in sync.
+var grafanaCloudSecretRoleTTLAliases = map[string]string{
+	"ttl":     "ttl_seconds",
+	"max_ttl": "max_ttl_seconds",
+}
+
+// grafanaCloudSecretRoleDefaultTTL is the TTL in seconds of a role that
+// configures neither a duration nor seconds.
+const grafanaCloudSecretRoleDefaultTTL = 300
+
+// setGrafanaCloudSecretRole sets the role attributes from a response of
+// <backend>/roles/<name>.
+func setGrafanaCloudSecretRole(d *schema.ResourceData, resp *api.Secret) error {
+	for _, k := range grafanaCloudSecretRoleFields {
+		if val, ok := resp.Data[k]; ok {
+			if err := d.Set(k, val); err != nil {
+				return fmt.Errorf("error setting state key '%s': %s", k, err)
+			}
+		}
+	}
+	for durationKey, secondsKey := range grafanaCloudSecretRoleTTLAliases {
+		if err := d.Set(durationKey, strconv.Itoa(d.Get(secondsKey).(int))); err != nil {
+			return fmt.Errorf("error setting state key '%s': %s", durationKey, err)
 		}
 	}
 	return nil
 }
 
 func grafanaCloudSecretRoleUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*api.Client)
-	rolePath := d.Id()
-	log.Printf("[DEBUG] Updating %q", rolePath)
-
-	data := map[string]interface{}{}
-	if raw, ok := d.GetOk("gc_role"); ok {
-		data["gc_role"] = raw
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
 	}
-	if raw, ok := d.GetOk("ttl_seconds"); ok {
-		data["ttl_seconds"] = raw
+	backend, name, err := parseGrafanaCloudSecretRoleID(d.Id())
+	if err != nil {
+		return err
 	}
-	if raw, ok := d.GetOk("max_ttl_seconds"); ok {
-		data["max_ttl_seconds"] = raw
+	rolePath := fmt.Sprintf("%s/roles/%s", backend, name)
+
+	// A changed backend is usually the result of the backend being remounted,
+	// which moves its roles along with it, leaving the old path empty.
+	// Otherwise the role is moved by writing it to the new backend and
+	// deleting it from the old one, unless the new backend already has a role
+	// of the same name, which would be overwritten.
+	var oldRolePath string
+	if d.HasChange("backend") {
+		backend = d.Get("backend").(string)
+		newRolePath := fmt.Sprintf("%s/roles/%s", backend, name)
+		newResp, err := client.Logical().Read(newRolePath)
+		if err != nil {
+			return vaultError(err, "reading", newRolePath, "read")
+		}
+		oldResp, err := client.Logical().Read(rolePath)
+		if err != nil && !isNotFound(err) {
+			return vaultError(err, "reading", rolePath, "read")
+		}
+		if newResp != nil && oldResp != nil {
+			return fmt.Errorf("cannot move %q to %q, as a role named %q already exists on backend %q; import or delete it first", rolePath, newRolePath, name, backend)
+		}
+		if newResp == nil {
+			oldRolePath = rolePath
+		}
+		rolePath = newRolePath
 	}
-	if _, err := client.Logical().Write(rolePath, data); err != nil {
-		return fmt.Errorf("error updating template auth backend role %q: %s", rolePath, err)
+	log.Printf("[DEBUG] Updating %q", rolePath)
+
+	// Only the changed fields are sent, including zero values, so that e.g.
+	// ttl_seconds can be reset to 0. A role moved to a backend that does not
+	// have it yet is written in full.
+	full := map[string]interface{}{}
+	patch := map[string]interface{}{}
+	for _, k := range grafanaCloudSecretRoleFields {
+		full[k] = grafanaCloudSecretRoleField(d, k)
+		if d.HasChange(k) {
+			patch[k] = full[k]
+		}
 	}
+	if oldRolePath != "" {
+		if _, err := client.Logical().Write(rolePath, full); err != nil {
+			return vaultError(err, "writing", rolePath, "create")
+		}
+	} else if err := patchOrWrite(client, rolePath, patch, full); err != nil {
+		return err
+	}
+	d.SetId(grafanaCloudSecretRoleID(backend, name))
 	log.Printf("[DEBUG] Updated %q", rolePath)
+
+	if oldRolePath != "" {
+		log.Printf("[DEBUG] Deleting %q", oldRolePath)
+		if _, err := client.Logical().Delete(oldRolePath); err != nil && !isNotFound(err) {
+			return vaultError(err, "deleting", oldRolePath, "delete")
+		}
+		log.Printf("[DEBUG] Deleted %q", oldRolePath)
+	}
 	return grafanaCloudSecretRoleRead(d, meta)
 }
 
-func gcSecretRoleNameFromPath(path string) (string, error) {
-	if !gcSecretRoleNameFromPathRegex.MatchString(path) {
-		return "", fmt.Errorf("no name found")
+// grafanaCloudSecretRoleTTLComputedKeys are the attributes planned from the
+// lease TTLs of a role's mount.
+var grafanaCloudSecretRoleTTLComputedKeys = []string{
+	"effective_ttl_seconds",
+	"effective_max_ttl_seconds",
+	"mount_default_lease_ttl_seconds",
+	"mount_max_lease_ttl_seconds",
+}
+
+// grafanaCloudSecretRoleCustomizeDiff rejects TTLs the plugin would reject,
+// before anything is written to Vault, and plans the effective TTLs from the
+// lease TTLs of the mount. TTLs that are not known until apply are left to
+// the plugin.
+func grafanaCloudSecretRoleCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	ttl, ttlKnown, err := grafanaCloudSecretRoleDiffTTL(d, "ttl")
+	if err != nil {
+		return err
+	}
+	maxTTL, maxTTLKnown, err := grafanaCloudSecretRoleDiffTTL(d, "max_ttl")
+	if err != nil {
+		return err
 	}
-	res := gcSecretRoleNameFromPathRegex.FindStringSubmatch(path)
-	if len(res) != 2 {
-		return "", fmt.Errorf("unexpected number of matches (%d) for name", len(res))
+	if !ttlKnown || !maxTTLKnown {
+		return setNewComputed(d, grafanaCloudSecretRoleTTLComputedKeys...)
 	}
-	return res[1], nil
+	if err := validateGrafanaCloudSecretRoleTTLs(ttl, maxTTL); err != nil {
+		return err
+	}
+	if meta == nil || !d.NewValueKnown("backend") {
+		return setNewComputed(d, grafanaCloudSecretRoleTTLComputedKeys...)
+	}
+
+	client, err := getClient(d, meta)
+	if err != nil {
+		return err
+	}
+	backend := normaliseBackend(d.Get("backend"))
+	tunePath := fmt.Sprintf("sys/mounts/%s/tune", backend)
+	log.Printf("[DEBUG] Reading %q", tunePath)
+	tune, err := client.Sys().MountConfig(backend)
+	if err != nil {
+		// The mount may not exist until it is created by this plan.
+		log.Printf("[WARN] %s, skipping the TTL checks of role %q", vaultError(err, "reading", tunePath, "read"), d.Get("name"))
+		if d.Id() == "" || d.HasChanges("backend", "ttl", "max_ttl") {
+			return setNewComputed(d, grafanaCloudSecretRoleTTLComputedKeys...)
+		}
+		return nil
+	}
+	log.Printf("[DEBUG] Read %q", tunePath)
+
+	if problems := grafanaCloudSecretRoleTTLProblems(backend, ttl, maxTTL, tune); len(problems) > 0 && d.Get("strict_ttls").(bool) {
+		return fmt.Errorf("%s; set strict_ttls to false to only warn", strings.Join(problems, "; "))
+	}
+	effectiveTTL, effectiveMaxTTL := effectiveGrafanaCloudSecretRoleTTLs(ttl, maxTTL, tune)
+	for k, v := range map[string]int{
+		"effective_ttl_seconds":           effectiveTTL,
+		"effective_max_ttl_seconds":       effectiveMaxTTL,
+		"mount_default_lease_ttl_seconds": tune.DefaultLeaseTTL,
+		"mount_max_lease_ttl_seconds":     tune.MaxLeaseTTL,
+	} {
+		if err := d.SetNew(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// grafanaCloudSecretRoleDiffTTL plans a duration attribute and its
+// deprecated alias in seconds from whichever of them is configured, and
+// returns the TTL in seconds if it is known.
+func grafanaCloudSecretRoleDiffTTL(d *schema.ResourceDiff, durationKey string) (int, bool, error) {
+	secondsKey := grafanaCloudSecretRoleTTLAliases[durationKey]
+	config := d.GetRawConfig()
+	if config.IsNull() || !config.IsKnown() {
+		return d.Get(secondsKey).(int), d.NewValueKnown(secondsKey), nil
+	}
+	duration, seconds := config.GetAttr(durationKey), config.GetAttr(secondsKey)
+	if !duration.IsKnown() || !seconds.IsKnown() {
+		return 0, false, setNewComputed(d, durationKey, secondsKey)
+	}
+
+	ttl := grafanaCloudSecretRoleDefaultTTL
+	switch {
+	case !duration.IsNull():
+		var err error
+		if ttl, err = parseDurationSeconds(duration.AsString()); err != nil {
+			return 0, false, fmt.Errorf("invalid %s: %s", durationKey, err)
+		}
+	case !seconds.IsNull():
+		i, _ := seconds.AsBigFloat().Int64()
+		ttl = int(i)
+	}
+	if err := d.SetNew(durationKey, strconv.Itoa(ttl)); err != nil {
+		return 0, false, err
+	}
+	if err := d.SetNew(secondsKey, ttl); err != nil {
+		return 0, false, err
+	}
+	return ttl, true, nil
+}
+
+func setNewComputed(d *schema.ResourceDiff, keys ...string) error {
+	for _, k := range keys {
+		if err := d.SetNewComputed(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// effectiveGrafanaCloudSecretRoleTTLs returns the TTLs Vault issues a role's
+// credentials with, given the lease TTLs of its mount. A TTL of 0 uses the
+// mount's, and the mount's max lease TTL caps both.
+func effectiveGrafanaCloudSecretRoleTTLs(ttl, maxTTL int, tune *api.MountConfigOutput) (int, int) {
+	if maxTTL == 0 || maxTTL > tune.MaxLeaseTTL {
+		maxTTL = tune.MaxLeaseTTL
+	}
+	if ttl == 0 {
+		ttl = tune.DefaultLeaseTTL
+	}
+	if ttl > maxTTL {
+		ttl = maxTTL
+	}
+	return ttl, maxTTL
+}
+
+// grafanaCloudSecretRoleTTLProblems describes the configured TTLs of a role
+// that exceed the default lease TTL of its mount, or that Vault caps at the
+// max lease TTL of its mount.
+func grafanaCloudSecretRoleTTLProblems(backend string, ttl, maxTTL int, tune *api.MountConfigOutput) []string {
+	var problems []string
+	for _, t := range []struct {
+		key     string
+		seconds int
+	}{{"ttl", ttl}, {"max_ttl", maxTTL}} {
+		switch {
+		case t.seconds > tune.MaxLeaseTTL:
+			problems = append(problems, fmt.Sprintf("%s (%ds) exceeds the max lease TTL of mount %q, so Vault caps it at %ds", t.key, t.seconds, backend, tune.MaxLeaseTTL))
+		case t.seconds > tune.DefaultLeaseTTL:
+			problems = append(problems, fmt.Sprintf("%s (%ds) exceeds the default lease TTL of mount %q (%ds)", t.key, t.seconds, backend, tune.DefaultLeaseTTL))
+		}
+	}
+	return problems
+}
+
+// grafanaCloudSecretRoleTTLNotes describes how the default lease TTL of a
+// role's mount applies to its TTLs. Unlike the problems, they never fail the
+// plan.
+func grafanaCloudSecretRoleTTLNotes(backend string, ttl, maxTTL int, tune *api.MountConfigOutput) []string {
+	if ttl != 0 {
+		return nil
+	}
+	effectiveTTL, _ := effectiveGrafanaCloudSecretRoleTTLs(ttl, maxTTL, tune)
+	return []string{fmt.Sprintf("ttl is 0, so it falls back to the default lease TTL of mount %q and Vault issues credentials for %ds", backend, effectiveTTL)}
+}
+
+// grafanaCloudSecretRolePlanWarnings warns about how the lease TTLs of the
+// role's mount, as planned by CustomizeDiff, apply to its TTLs. The problems
+// only fail the plan with strict_ttls, so they are only warned about without
+// it.
+func grafanaCloudSecretRolePlanWarnings(planned map[string]cty.Value) []string {
+	strict, backend := planned["strict_ttls"], planned["backend"]
+	if !strict.IsKnown() || strict.IsNull() || !backend.IsKnown() || backend.IsNull() {
+		return nil
+	}
+	ints := map[string]int{}
+	for _, k := range []string{"ttl_seconds", "max_ttl_seconds", "mount_default_lease_ttl_seconds", "mount_max_lease_ttl_seconds"} {
+		v := planned[k]
+		if v.IsNull() || !v.IsKnown() {
+			return nil
+		}
+		i, _ := v.AsBigFloat().Int64()
+		ints[k] = int(i)
+	}
+	tune := &api.MountConfigOutput{
+		DefaultLeaseTTL: ints["mount_default_lease_ttl_seconds"],
+		MaxLeaseTTL:     ints["mount_max_lease_ttl_seconds"],
+	}
+
+	var warnings []string
+	if strict.False() {
+		warnings = grafanaCloudSecretRoleTTLProblems(backend.AsString(), ints["ttl_seconds"], ints["max_ttl_seconds"], tune)
+	}
+	return append(warnings, grafanaCloudSecretRoleTTLNotes(backend.AsString(), ints["ttl_seconds"], ints["max_ttl_seconds"], tune)...)
+}
+
+// grafanaCloudSecretRoleStateTypeV1 returns the state type of a role from
+// before ttl and max_ttl. Its schema is a frozen copy of the schema at the
+// time, so that later changes to the resource do not change it.
+func grafanaCloudSecretRoleStateTypeV1() cty.Type {
+	return (&schema.Resource{Schema: grafanaCloudSecretRoleSchemaV1()}).CoreConfigSchema().ImpliedType()
+}
+
+// grafanaCloudSecretRoleStateTypeV2 returns the state type of a role from
+// before its ID escaped the backend and name, which is the version 1 schema
+// with ttl and max_ttl.
+func grafanaCloudSecretRoleStateTypeV2() cty.Type {
+	s := grafanaCloudSecretRoleSchemaV1()
+	s["ttl"] = &schema.Schema{Type: schema.TypeString, Optional: true, Computed: true}
+	s["max_ttl"] = &schema.Schema{Type: schema.TypeString, Optional: true, Computed: true}
+	return (&schema.Resource{Schema: s}).CoreConfigSchema().ImpliedType()
+}
+
+func grafanaCloudSecretRoleSchemaV1() map[string]*schema.Schema {
+	optional := func(t schema.ValueType) *schema.Schema {
+		return &schema.Schema{Type: t, Optional: true}
+	}
+	required := func(t schema.ValueType) *schema.Schema {
+		return &schema.Schema{Type: t, Required: true}
+	}
+	computed := func(t schema.ValueType) *schema.Schema {
+		return &schema.Schema{Type: t, Computed: true}
+	}
+	return map[string]*schema.Schema{
+		"backend":                   optional(schema.TypeString),
+		"namespace":                 optional(schema.TypeString),
+		"name":                      required(schema.TypeString),
+		"gc_role":                   required(schema.TypeString),
+		"ttl_seconds":               optional(schema.TypeInt),
+		"max_ttl_seconds":           optional(schema.TypeInt),
+		"strict_ttls":               optional(schema.TypeBool),
+		"effective_ttl_seconds":     computed(schema.TypeInt),
+		"effective_max_ttl_seconds": computed(schema.TypeInt),
+	}
+}
+
+// grafanaCloudSecretRoleStateUpgradeV1 sets ttl and max_ttl from the
+// ttl_seconds and max_ttl_seconds of a version 1 state.
+func grafanaCloudSecretRoleStateUpgradeV1(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return nil, nil
+	}
+	for durationKey, secondsKey := range grafanaCloudSecretRoleTTLAliases {
+		switch v := rawState[secondsKey].(type) {
+		case nil:
+		case float64:
+			rawState[durationKey] = strconv.Itoa(int(v))
+		case json.Number:
+			rawState[durationKey] = v.String()
+		default:
+			return nil, fmt.Errorf("unexpected type %T of %s", v, secondsKey)
+		}
+	}
+	log.Printf("[DEBUG] Upgraded the TTLs of %q", rawState["id"])
+	return rawState, nil
+}
+
+// grafanaCloudSecretRoleStateUpgradeV2 rewrites the legacy path ID of a
+// version 2 state to a role ID, from the state's backend and name, or else
+// from the path split at its last "/roles/".
+func grafanaCloudSecretRoleStateUpgradeV2(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return nil, nil
+	}
+	id, _ := rawState["id"].(string)
+	backend, _ := rawState["backend"].(string)
+	name, _ := rawState["name"].(string)
+	if backend == "" || name == "" {
+		candidates, err := legacyGrafanaCloudSecretRoleIDCandidates(id)
+		if err != nil {
+			return nil, err
+		}
+		backend, name = candidates[len(candidates)-1][0], candidates[len(candidates)-1][1]
+	}
+	rawState["id"] = grafanaCloudSecretRoleID(normaliseBackend(backend), name)
+	log.Printf("[DEBUG] Upgraded the ID of role %q to %q", id, rawState["id"])
+	return rawState, nil
 }
 
-func gcSecretFromPath(path string) (string, error) {
-	if !gcSecretFromPathRegex.MatchString(path) {
-		return "", fmt.Errorf("no backend found")
+// validateGrafanaCloudSecretRoleTTLs checks neither TTL is negative, and that
+// ttl does not exceed maxTTL. A maxTTL of 0 uses the mount default, which is
+// only known to Vault.
+func validateGrafanaCloudSecretRoleTTLs(ttl, maxTTL int) error {
+	if ttl < 0 {
+		return fmt.Errorf("ttl must not be negative, got %ds", ttl)
+	}
+	if maxTTL < 0 {
+		return fmt.Errorf("max_ttl must not be negative, got %ds", maxTTL)
+	}
+	if maxTTL > 0 && ttl > maxTTL {
+		return fmt.Errorf("ttl (%ds) must not be greater than max_ttl (%ds)", ttl, maxTTL)
+	}
+	return nil
+}
+
+// suppressCaseDiff is a schema.SchemaDiffSuppressFunc ignoring changes in
+// case only.
+func suppressCaseDiff(_, old, new string, _ *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+// grafanaCloudSecretRoleImport imports a role by its ID, optionally prefixed
+// with "<namespace>:", failing early if the ID cannot be parsed. Besides the
+// ID, which escapes slashes in the backend and name, the legacy
+// "<backend>/roles/<name>" path is accepted. A legacy path that is ambiguous,
+// as its backend contains "/roles/", is resolved against the mounted backends.
+func grafanaCloudSecretRoleImport(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id, err := importNamespace(d)
+	if err != nil {
+		return nil, err
+	}
+	id = strings.Trim(id, "/")
+
+	candidates, err := grafanaCloudSecretRoleIDCandidates(id)
+	if err != nil {
+		return nil, err
+	}
+	backend, name := candidates[0][0], candidates[0][1]
+	if len(candidates) > 1 {
+		client, err := getClient(d, meta)
+		if err != nil {
+			return nil, err
+		}
+		if backend, name, err = resolveGrafanaCloudSecretRoleID(client, id, candidates); err != nil {
+			return nil, err
+		}
+	}
+
+	d.SetId(grafanaCloudSecretRoleID(backend, name))
+	// defaults are not applied to imported resources
+	if err := d.Set("strict_ttls", false); err != nil {
+		return nil, fmt.Errorf("error setting strict_ttls: %s", err)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// resolveGrafanaCloudSecretRoleID picks the only candidate backend and name
+// of an ambiguous legacy role path whose backend is mounted.
+func resolveGrafanaCloudSecretRoleID(client *api.Client, id string, candidates [][2]string) (string, string, error) {
+	var found [][2]string
+	for _, c := range candidates {
+		mount, err := getGrafanaCloudMount(client, c[0])
+		if err != nil {
+			return "", "", err
+		}
+		if mount != nil {
+			found = append(found, c)
+		}
+	}
+	if len(found) != 1 {
+		return "", "", fmt.Errorf("ambiguous role ID %q: import it as %q instead, escaping the slashes in the backend", id, grafanaCloudSecretRoleID(candidates[0][0], candidates[0][1]))
+	}
+	return found[0][0], found[0][1], nil
+}
+
+// grafanaCloudSecretRoleID returns the ID of a role, "<backend>/roles/<name>"
+// with the backend and name path escaped, so that slashes in either cannot
+// make the ID ambiguous. The ID of a role on a backend without slashes is
+// its path.
+func grafanaCloudSecretRoleID(backend, name string) string {
+	return url.PathEscape(backend) + "/roles/" + url.PathEscape(name)
+}
+
+// parseGrafanaCloudSecretRoleID returns the backend and name of a role ID,
+// or of a legacy "<backend>/roles/<name>" ID, which is split at its last
+// "/roles/".
+func parseGrafanaCloudSecretRoleID(id string) (string, string, error) {
+	candidates, err := grafanaCloudSecretRoleIDCandidates(id)
+	if err != nil {
+		return "", "", err
+	}
+	last := candidates[len(candidates)-1]
+	return last[0], last[1], nil
+}
+
+// grafanaCloudSecretRoleIDCandidates returns the backends and names a role
+// ID can stand for. A role ID has exactly one. Anything else is read as a
+// legacy "<backend>/roles/<name>" path.
+func grafanaCloudSecretRoleIDCandidates(id string) ([][2]string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) == 3 && parts[1] == "roles" {
+		backend, err := url.PathUnescape(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid role ID %q: %s", id, err)
+		}
+		name, err := url.PathUnescape(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid role ID %q: %s", id, err)
+		}
+		if backend != "" && name != "" {
+			return [][2]string{{backend, name}}, nil
+		}
+	}
+	return legacyGrafanaCloudSecretRoleIDCandidates(id)
+}
+
+// legacyGrafanaCloudSecretRoleIDCandidates returns the backends and names a
+// legacy "<backend>/roles/<name>" path can stand for, one for each "/roles/"
+// it contains, in order. Legacy paths were never escaped, so they are split
+// as they are.
+func legacyGrafanaCloudSecretRoleIDCandidates(id string) ([][2]string, error) {
+	parts := strings.Split(id, "/")
+	var candidates [][2]string
+	for i := 1; i < len(parts)-1; i++ {
+		backend, name := strings.Join(parts[:i], "/"), strings.Join(parts[i+1:], "/")
+		if parts[i] == "roles" && backend != "" && name != "" {
+			candidates = append(candidates, [2]string{backend, name})
+		}
 	}
-	res := gcSecretFromPathRegex.FindStringSubmatch(path)
-	if len(res) != 2 {
-		return "", fmt.Errorf("unexpected number of matches (%d) for backend", len(res))
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("invalid role ID %q: expected \"<backend>/roles/<name>\"", id)
 	}
-	return res[1], nil
+	return candidates, nil
 }
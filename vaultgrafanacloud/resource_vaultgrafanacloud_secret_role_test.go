@@ -1,14 +1,23 @@
 package vaultgrafanacloud
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/form3tech-oss/terraform-provider-vault-grafanacloud/testutil"
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/hashicorp/vault/api"
 )
@@ -51,12 +60,292 @@ func TestGrafanaCloudSecretRole(t *testing.T) {
 					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "max_ttl_seconds", updatedMaxTTL),
 				),
 			},
+			{
+				// zero values are sent rather than dropped
+				Config: testGrafanaCloudSecretRole_updateConfig(backend, key, url, organisation, name, user, updatedGCRole, "0", updatedMaxTTL),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "ttl_seconds", "0"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "max_ttl_seconds", updatedMaxTTL),
+				),
+			},
+			{
+				ResourceName:      "vaultgrafanacloud_secret_role.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGrafanaCloudSecretRole_validation(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	name := uuid.New().String()
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config:      testGrafanaCloudSecretRole_initialConfig(backend, "key", "http://localhost", "test_org", "user", name, "Reader", "1", "2"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`expected gc_role to be one of`),
+			},
+			{
+				Config:      testGrafanaCloudSecretRole_initialConfig(backend, "key", "http://localhost", "test_org", "user", name, "Viewer", "3", "2"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`ttl \(3s\) must not be greater than max_ttl \(2s\)`),
+			},
+			{
+				Config:      testGrafanaCloudSecretRole_initialConfig(backend, "key", "http://localhost", "test_org", "user", name, "Viewer", "-1", "2"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`ttl must not be negative`),
+			},
+			{
+				Config: testGrafanaCloudSecretRole_initialConfig(backend, "key", "http://localhost", "test_org", "user", name, "Viewer", "1", "2"),
+			},
+			{
+				// a change in case only is suppressed
+				Config:   testGrafanaCloudSecretRole_initialConfig(backend, "key", "http://localhost", "test_org", "user", name, "viewer", "1", "2"),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestValidateGrafanaCloudSecretRoleTTLs(t *testing.T) {
+	tests := map[string]struct {
+		ttl, maxTTL int
+		wantErr     bool
+	}{
+		"equal":             {ttl: 300, maxTTL: 300},
+		"below max":         {ttl: 60, maxTTL: 300},
+		"mount default ttl": {ttl: 0, maxTTL: 300},
+		"mount default max": {ttl: 600, maxTTL: 0},
+		"above max":         {ttl: 600, maxTTL: 300, wantErr: true},
+		"negative ttl":      {ttl: -1, maxTTL: 300, wantErr: true},
+		"negative max":      {ttl: 0, maxTTL: -1, wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateGrafanaCloudSecretRoleTTLs(tt.ttl, tt.maxTTL)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Errorf("expected error %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGrafanaCloudSecretRoleCreate_gcRoleCase(t *testing.T) {
+	var written map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/sys/mounts":
+			_, _ = w.Write([]byte(`{"data": {}}`))
+		case r.URL.Path == "/v1/grafana-cloud/roles/test" && r.Method == http.MethodPut:
+			if err := json.NewDecoder(r.Body).Decode(&written); err != nil {
+				t.Errorf("error decoding request: %s", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/v1/grafana-cloud/roles/test":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": written})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	config.MaxRetries = 0
+	client, err := api.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, grafanaCloudSecretRoleSchema(), map[string]interface{}{
+		"backend":         "grafana-cloud",
+		"name":            "test",
+		"gc_role":         "viewer",
+		"ttl_seconds":     300,
+		"max_ttl_seconds": 300,
+	})
+	d.MarkNewResource()
+	if err := grafanaCloudSecretRoleCreate(d, testProviderMeta(client)); err != nil {
+		t.Fatal(err)
+	}
+	if written["gc_role"] != "Viewer" {
+		t.Errorf("expected gc_role to be written as %q, got %v", "Viewer", written["gc_role"])
+	}
+}
+
+func TestEffectiveGrafanaCloudSecretRoleTTLs(t *testing.T) {
+	tune := &api.MountConfigOutput{DefaultLeaseTTL: 600, MaxLeaseTTL: 3600}
+	tests := map[string]struct {
+		ttl, maxTTL         int
+		wantTTL, wantMaxTTL int
+		wantProblems        int
+		wantNotes           int
+	}{
+		"within limits":          {ttl: 300, maxTTL: 600, wantTTL: 300, wantMaxTTL: 600},
+		"mount defaults":         {ttl: 0, maxTTL: 0, wantTTL: 600, wantMaxTTL: 3600, wantNotes: 1},
+		"mount default capped":   {ttl: 0, maxTTL: 300, wantTTL: 300, wantMaxTTL: 300, wantNotes: 1},
+		"above mount default":    {ttl: 1800, maxTTL: 0, wantTTL: 1800, wantMaxTTL: 3600, wantProblems: 1},
+		"above default with max": {ttl: 1800, maxTTL: 1800, wantTTL: 1800, wantMaxTTL: 1800, wantProblems: 2},
+		"max capped":             {ttl: 300, maxTTL: 7200, wantTTL: 300, wantMaxTTL: 3600, wantProblems: 1},
+		"both capped":            {ttl: 7200, maxTTL: 7200, wantTTL: 3600, wantMaxTTL: 3600, wantProblems: 2},
+		"ttl capped by max":      {ttl: 7200, maxTTL: 0, wantTTL: 3600, wantMaxTTL: 3600, wantProblems: 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ttl, maxTTL := effectiveGrafanaCloudSecretRoleTTLs(tt.ttl, tt.maxTTL, tune)
+			if ttl != tt.wantTTL || maxTTL != tt.wantMaxTTL {
+				t.Errorf("expected %d and %d, got %d and %d", tt.wantTTL, tt.wantMaxTTL, ttl, maxTTL)
+			}
+			problems := grafanaCloudSecretRoleTTLProblems("grafana-cloud", tt.ttl, tt.maxTTL, tune)
+			if len(problems) != tt.wantProblems {
+				t.Errorf("expected %d problems, got %v", tt.wantProblems, problems)
+			}
+			notes := grafanaCloudSecretRoleTTLNotes("grafana-cloud", tt.ttl, tt.maxTTL, tune)
+			if len(notes) != tt.wantNotes {
+				t.Errorf("expected %d notes, got %v", tt.wantNotes, notes)
+			}
+		})
+	}
+}
+
+func TestGrafanaCloudSecretRole_mountLeaseTTLs(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	name := uuid.New().String()
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretRole_mountLeaseTTLsConfig(backend, name, 7200, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "max_ttl_seconds", "7200"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "effective_max_ttl_seconds", "3600"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "effective_ttl_seconds", "300"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "mount_max_lease_ttl_seconds", "3600"),
+				),
+			},
+			{
+				Config:      testGrafanaCloudSecretRole_mountLeaseTTLsConfig(backend, name, 7200, true),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`max_ttl \(7200s\) exceeds the max lease TTL`),
+			},
+			{
+				Config: testGrafanaCloudSecretRole_mountLeaseTTLsConfig(backend, name, 1800, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "effective_max_ttl_seconds", "1800"),
+				),
+			},
+		},
+	})
+}
+
+func testGrafanaCloudSecretRole_mountLeaseTTLsConfig(backend, name string, maxTTL int, strict bool) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+	max_lease_ttl_seconds = 3600
+}
+
+resource "vaultgrafanacloud_secret_role" "test" {
+	backend = vaultgrafanacloud_secret_backend.test.backend
+	name = "%s"
+	gc_role = "Viewer"
+	ttl_seconds = 300
+	max_ttl_seconds = %d
+	strict_ttls = %t
+}
+`, backend, name, maxTTL, strict)
+}
+
+func TestGrafanaCloudSecretRole_durations(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	name := uuid.New().String()
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretRole_durationsConfig(backend, name, `ttl = "1h"`, `max_ttl = "1h30m"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "ttl", "3600"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "ttl_seconds", "3600"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "max_ttl", "5400"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "max_ttl_seconds", "5400"),
+				),
+			},
+			{
+				// the same TTLs in seconds do not change the role
+				Config:   testGrafanaCloudSecretRole_durationsConfig(backend, name, `ttl_seconds = 3600`, `max_ttl = "5400"`),
+				PlanOnly: true,
+			},
+			{
+				// unset TTLs revert to the default
+				Config: testGrafanaCloudSecretRole_durationsConfig(backend, name, "", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "ttl", "300"),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "max_ttl_seconds", "300"),
+				),
+			},
+			{
+				Config:      testGrafanaCloudSecretRole_durationsConfig(backend, name, `ttl = "1h"`, `ttl_seconds = 3600`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`"ttl": conflicts with ttl_seconds`),
+			},
 		},
 	})
 }
 
+func testGrafanaCloudSecretRole_durationsConfig(backend, name, ttl, maxTTL string) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "test" {
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+}
+
+resource "vaultgrafanacloud_secret_role" "test" {
+	backend = vaultgrafanacloud_secret_backend.test.backend
+	name = "%s"
+	gc_role = "Viewer"
+	%s
+	%s
+}
+`, backend, name, ttl, maxTTL)
+}
+
+func TestGrafanaCloudSecretRoleStateUpgradeV1(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":              "grafana-cloud/roles/test",
+		"ttl_seconds":     float64(3600),
+		"max_ttl_seconds": json.Number("5400"),
+	}
+	got, err := grafanaCloudSecretRoleStateUpgradeV1(context.Background(), rawState, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["ttl"] != "3600" || got["max_ttl"] != "5400" {
+		t.Errorf("expected ttl 3600 and max_ttl 5400, got %v", got)
+	}
+	if got["ttl_seconds"] != float64(3600) {
+		t.Errorf("expected ttl_seconds to be kept, got %v", got)
+	}
+}
+
 func testAccGrafanaCloudSecretRoleCheckDestroy(s *terraform.State) error {
-	client := testProvider.Meta().(*api.Client)
+	client := testProvider.Meta().(*providerMeta).client
 
 	mounts, err := client.Sys().ListMounts()
 	if err != nil {
@@ -116,3 +405,307 @@ resource "vaultgrafanacloud_secret_role" "test" {
 	max_ttl_seconds = %v
 }`, backend, key, url, organisation, name, user, gcRole, ttl, maxTTL)
 }
+
+func TestGrafanaCloudSecretRole_namespace(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	namespace := acctest.RandomWithPrefix("tf-test-ns")
+	name := uuid.New().String()
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck: func() {
+			testutil.TestEntPreCheck(t)
+			testAccNamespace(t, namespace)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_backend" "test" {
+	namespace = "%s"
+	backend = "%s"
+	key = "key"
+	url = "http://localhost"
+	organisation = "test_org"
+	user = "user"
+}
+
+resource "vaultgrafanacloud_secret_role" "test" {
+	namespace = vaultgrafanacloud_secret_backend.test.namespace
+	backend = vaultgrafanacloud_secret_backend.test.backend
+	name = "%s"
+	gc_role = "Viewer"
+}`, namespace, backend, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "namespace", namespace),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "name", name),
+				),
+			},
+			{
+				ResourceName:      "vaultgrafanacloud_secret_role.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s:%s/roles/%s", namespace, backend, name),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGrafanaCloudSecretRole_nestedBackend(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud") + "/roles/grafana"
+	name := "viewer"
+	id := url.PathEscape(backend) + "/roles/" + name
+
+	resource.Test(t, resource.TestCase{
+		Providers:    testProviders,
+		PreCheck:     func() { testutil.TestAccPreCheck(t) },
+		CheckDestroy: testAccGrafanaCloudSecretRoleCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testGrafanaCloudSecretRole_durationsConfig(backend, name, "", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "id", id),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "backend", backend),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "name", name),
+				),
+			},
+			{
+				ResourceName:      "vaultgrafanacloud_secret_role.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				// the legacy path is resolved against the mounted backends
+				ResourceName:      "vaultgrafanacloud_secret_role.test",
+				ImportState:       true,
+				ImportStateId:     fmt.Sprintf("%s/roles/%s", backend, name),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestGrafanaCloudSecretRole_remountedOutsideConfig(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	newBackend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	name := uuid.New().String()
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				PreConfig: testAccGrafanaCloudSecretBackendMount(t, backend, grafanaCloudPluginType),
+				Config:    testGrafanaCloudSecretRole_literalBackendConfig(backend, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "id", fmt.Sprintf("%s/roles/%s", backend, name)),
+					resource.TestCheckResourceAttrSet("vaultgrafanacloud_secret_role.test", "mount_accessor"),
+				),
+			},
+			{
+				// the role follows the mount rather than being recreated
+				PreConfig: testAccGrafanaCloudSecretRoleRemount(t, backend, newBackend),
+				Config:    testGrafanaCloudSecretRole_literalBackendConfig(newBackend, name),
+				PlanOnly:  true,
+			},
+			{
+				Config: testGrafanaCloudSecretRole_literalBackendConfig(newBackend, name),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "id", fmt.Sprintf("%s/roles/%s", newBackend, name)),
+					resource.TestCheckResourceAttr("vaultgrafanacloud_secret_role.test", "backend", newBackend),
+				),
+			},
+		},
+	})
+}
+
+func TestGrafanaCloudSecretRole_moveCollision(t *testing.T) {
+	backend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	newBackend := acctest.RandomWithPrefix("tf-test-grafanacloud")
+	name := uuid.New().String()
+
+	resource.Test(t, resource.TestCase{
+		Providers: testProviders,
+		PreCheck:  func() { testutil.TestAccPreCheck(t) },
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					testAccGrafanaCloudSecretBackendMount(t, backend, grafanaCloudPluginType)()
+					testAccGrafanaCloudSecretBackendMount(t, newBackend, grafanaCloudPluginType)()
+				},
+				Config: testGrafanaCloudSecretRole_literalBackendConfig(backend, name),
+			},
+			{
+				PreConfig: func() {
+					rolePath := fmt.Sprintf("%s/roles/%s", newBackend, name)
+					if _, err := testAccClient(t).Logical().Write(rolePath, map[string]interface{}{"gc_role": "Admin"}); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:      testGrafanaCloudSecretRole_literalBackendConfig(newBackend, name),
+				ExpectError: regexp.MustCompile(`already exists on backend`),
+			},
+		},
+	})
+}
+
+func testGrafanaCloudSecretRole_literalBackendConfig(backend, name string) string {
+	return fmt.Sprintf(`
+resource "vaultgrafanacloud_secret_role" "test" {
+	backend = "%s"
+	name = "%s"
+	gc_role = "Viewer"
+}`, backend, name)
+}
+
+// testAccGrafanaCloudSecretRoleRemount remounts a backend outside of
+// Terraform, unmounting it at the end of the test.
+func testAccGrafanaCloudSecretRoleRemount(t *testing.T, from, to string) func() {
+	return func() {
+		client := testAccClient(t)
+		if err := remount(client, from, to, time.Minute); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if err := client.Sys().Unmount(to); err != nil && !isNotFound(err) {
+				t.Errorf("error unmounting %q: %s", to, err)
+			}
+		})
+	}
+}
+
+func TestGrafanaCloudSecretRoleStateTypes(t *testing.T) {
+	v1, v2 := grafanaCloudSecretRoleStateTypeV1(), grafanaCloudSecretRoleStateTypeV2()
+	for _, k := range []string{"ttl_seconds", "strict_ttls", "effective_ttl_seconds"} {
+		if !v1.HasAttribute(k) || !v2.HasAttribute(k) {
+			t.Errorf("expected the version 1 and 2 states to have %s", k)
+		}
+	}
+	for _, k := range []string{"ttl", "max_ttl"} {
+		if v1.HasAttribute(k) {
+			t.Errorf("expected the version 1 state not to have %s", k)
+		}
+		if !v2.HasAttribute(k) {
+			t.Errorf("expected the version 2 state to have %s", k)
+		}
+	}
+	if v2.HasAttribute("mount_accessor") {
+		t.Error("expected the version 2 state not to have mount_accessor")
+	}
+}
+
+func TestParseGrafanaCloudSecretRoleID(t *testing.T) {
+	tests := map[string]struct {
+		id          string
+		wantBackend string
+		wantName    string
+		wantErr     bool
+	}{
+		"simple": {
+			id:          "grafana-cloud/roles/viewer",
+			wantBackend: "grafana-cloud",
+			wantName:    "viewer",
+		},
+		"escaped backend": {
+			id:          "teams%2Froles%2Fgrafana/roles/viewer",
+			wantBackend: "teams/roles/grafana",
+			wantName:    "viewer",
+		},
+		"escaped name": {
+			id:          "grafana-cloud/roles/team%2Fviewer",
+			wantBackend: "grafana-cloud",
+			wantName:    "team/viewer",
+		},
+		"legacy nested backend": {
+			id:          "teams/roles/grafana/roles/viewer",
+			wantBackend: "teams/roles/grafana",
+			wantName:    "viewer",
+		},
+		"legacy slashes in backend": {
+			id:          "teams/grafana/roles/viewer",
+			wantBackend: "teams/grafana",
+			wantName:    "viewer",
+		},
+		"no roles": {
+			id:      "grafana-cloud/viewer",
+			wantErr: true,
+		},
+		"no name": {
+			id:      "grafana-cloud/roles/",
+			wantErr: true,
+		},
+		"bad escape": {
+			id:      "grafana%zz/roles/viewer",
+			wantErr: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			backend, roleName, err := parseGrafanaCloudSecretRoleID(tt.id)
+			if gotErr := err != nil; gotErr != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+			if backend != tt.wantBackend || roleName != tt.wantName {
+				t.Errorf("expected %q and %q, got %q and %q", tt.wantBackend, tt.wantName, backend, roleName)
+			}
+			if err == nil {
+				b, n, err := parseGrafanaCloudSecretRoleID(grafanaCloudSecretRoleID(backend, roleName))
+				if err != nil || b != backend || n != roleName {
+					t.Errorf("expected the ID of %q and %q to round trip, got %q and %q: %v", backend, roleName, b, n, err)
+				}
+			}
+		})
+	}
+}
+
+func TestGrafanaCloudSecretRoleIDCandidates(t *testing.T) {
+	got, err := grafanaCloudSecretRoleIDCandidates("teams/roles/grafana/roles/viewer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"teams", "grafana/roles/viewer"}, {"teams/roles/grafana", "viewer"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestGrafanaCloudSecretRoleStateUpgradeV2(t *testing.T) {
+	tests := map[string]struct {
+		rawState map[string]interface{}
+		wantID   string
+	}{
+		"backend and name": {
+			rawState: map[string]interface{}{"id": "teams/grafana/roles/viewer", "backend": "teams/grafana", "name": "viewer"},
+			wantID:   "teams%2Fgrafana/roles/viewer",
+		},
+		"legacy path": {
+			rawState: map[string]interface{}{"id": "teams/roles/grafana/roles/viewer"},
+			wantID:   "teams%2Froles%2Fgrafana/roles/viewer",
+		},
+		"unescaped legacy path": {
+			rawState: map[string]interface{}{"id": "grafana-cloud/roles/50%off"},
+			wantID:   "grafana-cloud/roles/50%25off",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := grafanaCloudSecretRoleStateUpgradeV2(context.Background(), tt.rawState, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got["id"] != tt.wantID {
+				t.Errorf("expected ID %q, got %q", tt.wantID, got["id"])
+			}
+		})
+	}
+}
+
+func TestLegacyGrafanaCloudSecretRoleIDCandidates(t *testing.T) {
+	got, err := legacyGrafanaCloudSecretRoleIDCandidates("teams%2F/roles/a%zz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"teams%2F", "a%zz"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,45 @@
+package vaultgrafanacloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// validateDuration is a schema.SchemaValidateFunc for duration strings such
+// as "1.5s".
+func validateDuration(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %q to be string", k)}
+	}
+	if d, err := time.ParseDuration(v); err != nil {
+		return nil, []error{fmt.Errorf("%q must be a duration string such as \"1.5s\", got %q: %s", k, v, err)}
+	} else if d < 0 {
+		return nil, []error{fmt.Errorf("%q must not be negative, got %q", k, v)}
+	}
+	return nil, nil
+}
+
+// readAfterWrite reads path, and if retryNotFound is set retries while Vault
+// reports it missing. This covers reads racing a preceding write, which
+// ReadYourWrites only solves on Vault Enterprise. It honours the client's
+// MaxRetries, MinRetryWait and MaxRetryWait.
+func readAfterWrite(client *api.Client, path string, retryNotFound bool) (*api.Secret, error) {
+	wait := client.MinRetryWait()
+	for attempt := 0; ; attempt++ {
+		resp, err := client.Logical().Read(path)
+		notFound := resp == nil && (err == nil || isNotFound(err))
+		if !retryNotFound || !notFound || attempt >= client.MaxRetries() {
+			return resp, err
+		}
+
+		log.Printf("[DEBUG] %q not found after write, retrying in %s", path, wait)
+		time.Sleep(wait)
+		if wait *= 2; wait > client.MaxRetryWait() {
+			wait = client.MaxRetryWait()
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package vaultgrafanacloud
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+)
+
+func TestReadAfterWrite(t *testing.T) {
+	tests := map[string]struct {
+		retryNotFound bool
+		notFoundFor   int
+		wantRequests  int
+		wantFound     bool
+	}{
+		"found": {
+			retryNotFound: true,
+			wantRequests:  1,
+			wantFound:     true,
+		},
+		"found after retries": {
+			retryNotFound: true,
+			notFoundFor:   2,
+			wantRequests:  3,
+			wantFound:     true,
+		},
+		"retries exhausted": {
+			retryNotFound: true,
+			notFoundFor:   5,
+			wantRequests:  4,
+		},
+		"no retry": {
+			notFoundFor:  2,
+			wantRequests: 1,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= tt.notFoundFor {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(`{"data": {"gc_role": "Viewer"}}`))
+			}))
+			defer server.Close()
+
+			config := api.DefaultConfig()
+			config.Address = server.URL
+			config.MaxRetries = 3
+			config.MinRetryWait = time.Millisecond
+			config.MaxRetryWait = time.Millisecond
+			client, err := api.NewClient(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := readAfterWrite(client, "grafana-cloud/roles/test", tt.retryNotFound)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if found := resp != nil; found != tt.wantFound {
+				t.Errorf("expected found %t, got %t", tt.wantFound, found)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("expected %d requests, got %d", tt.wantRequests, requests)
+			}
+		})
+	}
+}